@@ -4,5 +4,6 @@ go 1.19
 
 require (
 	github.com/go-phings/broccli v2.0.0+incompatible
-	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
+	mvdan.cc/sh/v3 v3.7.0
 )
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-phings/broccli"
 	"os"
+	"sort"
+	"strings"
 
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
 	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/graph"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/rule"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/trigger"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/validator"
 )
 
 func main() {
@@ -14,6 +23,24 @@ func main() {
 	cmdValidate.AddFlag("path", "p", "", "Path to .github directory", broccli.TypePathFile, broccli.IsDirectory|broccli.IsExistent|broccli.IsRequired)
 	cmdValidate.AddFlag("vars-file", "z", "", "Check if variable names exist in this file (one per line)", broccli.TypePathFile, broccli.IsExistent)
 	cmdValidate.AddFlag("secrets-file", "s", "", "Check if secret names exist in this file (one per line)", broccli.TypePathFile, broccli.IsExistent)
+	cmdValidate.AddFlag("config", "c", "", "Path to configuration file", broccli.TypePathFile, broccli.IsExistent|broccli.IsRegularFile)
+	cmdValidate.AddFlag("sort", "o", "", "Sort output by 'file' (default), 'code' or 'severity'", broccli.TypeString, 0)
+	cmdUsage := cli.AddCmd("usage", "Prints where local actions are used, with inputs passed to them", usageHandler)
+	cmdUsage.AddFlag("path", "p", "", "Path to .github directory", broccli.TypePathFile, broccli.IsDirectory|broccli.IsExistent|broccli.IsRequired)
+	cmdGraph := cli.AddCmd("graph", "Prints dependency graph of workflows, jobs, steps and actions", graphHandler)
+	cmdGraph.AddFlag("path", "p", "", "Path to .github directory", broccli.TypePathFile, broccli.IsDirectory|broccli.IsExistent|broccli.IsRequired)
+	cmdGraph.AddFlag("format", "f", "", "Output format: 'dot' (default) or 'mermaid'", broccli.TypeString, 0)
+	cmdGraph.AddFlag("without-steps", "w", "", "Connect jobs to the actions they call directly, without steps", broccli.TypeBool, 0, broccli.OnTrue(func(c *broccli.Cmd) {}))
+	cmdTriggers := cli.AddCmd("triggers", "Prints which workflows start for an event", triggersHandler)
+	cmdTriggers.AddFlag("path", "p", "", "Path to .github directory", broccli.TypePathFile, broccli.IsDirectory|broccli.IsExistent|broccli.IsRequired)
+	cmdTriggers.AddFlag("event", "e", "", "Event name, eg. 'push' or 'pull_request'", broccli.TypeString, broccli.IsRequired)
+	cmdTriggers.AddFlag("branch", "b", "", "Pushed branch, or base branch of a pull request", broccli.TypeString, 0)
+	cmdTriggers.AddFlag("tag", "t", "", "Pushed tag", broccli.TypeString, 0)
+	cmdTriggers.AddFlag("files", "f", "", "Changed files, separated by comma or space", broccli.TypeString, 0)
+	cmdTriggers.AddFlag("type", "a", "", "Activity type, eg. 'opened'", broccli.TypeString, 0)
+	cmdTriggers.AddFlag("workflow", "w", "", "Name of the workflow triggering 'workflow_run'", broccli.TypeString, 0)
+	cmdTriggers.AddFlag("jobs", "j", "", "Show jobs of triggered workflows whose 'if' can be decided statically", broccli.TypeBool, 0, broccli.OnTrue(func(c *broccli.Cmd) {}))
+	_ = cli.AddCmd("rules", "Prints all the checks with their codes", rulesHandler)
 	_ = cli.AddCmd("version", "Prints version", versionHandler)
 	if len(os.Args) == 2 && (os.Args[1] == "-v" || os.Args[1] == "--version") {
 		os.Args = []string{"App", "version"}
@@ -26,24 +53,200 @@ func versionHandler(c *broccli.CLI) int {
 	return 0
 }
 
+func rulesHandler(c *broccli.CLI) int {
+	for _, r := range rule.Builtin().Rules() {
+		fmt.Fprintf(os.Stdout, "%s: %-8s %s\n", r.Code(), r.Severity(), r.Description())
+	}
+	return 0
+}
+
 func validateHandler(c *broccli.CLI) int {
-	dotGithub := dotgithub.DotGithub{
-		Path:        c.Flag("path"),
-		VarsFile:    c.Flag("vars-file"),
-		SecretsFile: c.Flag("secrets-file"),
+	opts := validator.Options{
+		Path:   c.Flag("path"),
+		Output: os.Stdout,
 	}
-	err := dotGithub.InitFiles()
+	var err error
+	if c.Flag("config") != "" {
+		fmt.Fprintf(os.Stdout, "**** Reading config file %s ...\n", c.Flag("config"))
+		opts.Config, err = config.Load(c.Flag("config"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "!!!! Error with initialization: %s\n", err.Error())
+			return 1
+		}
+	}
+	if c.Flag("vars-file") != "" {
+		fmt.Fprintf(os.Stdout, "**** Reading file with list of possible variable names %s ...\n", c.Flag("vars-file"))
+		opts.Vars, err = readNames(c.Flag("vars-file"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "!!!! Error with initialization: %s\n", err.Error())
+			return 1
+		}
+	}
+	if c.Flag("secrets-file") != "" {
+		fmt.Fprintf(os.Stdout, "**** Reading file with list of possible secret names %s ...\n", c.Flag("secrets-file"))
+		opts.Secrets, err = readNames(c.Flag("secrets-file"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "!!!! Error with initialization: %s\n", err.Error())
+			return 1
+		}
+	}
+
+	report, err := validator.Run(context.Background(), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "!!!! Error with validation: %s\n", err.Error())
+		return 1
+	}
+	err = finding.Sort(report.Findings, c.Flag("sort"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "!!!! Error with sorting: %s\n", err.Error())
+		return 1
+	}
+	for _, verr := range report.Findings {
+		fmt.Fprintf(os.Stdout, "%s\n", verr)
+	}
+	return 0
+}
+
+func usageHandler(c *broccli.CLI) int {
+	d := &dotgithub.DotGithub{
+		Path:   c.Flag("path"),
+		Output: os.Stdout,
+	}
+	err := d.InitFiles()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "!!!! Error with initialization: %s\n", err.Error())
 		return 1
 	}
-	validationErrors, err := dotGithub.Validate()
+	for _, verr := range d.InitErrors() {
+		fmt.Fprintf(os.Stdout, "%s\n", verr)
+	}
+
+	usages := d.GetActionUsages()
+	unused := []string{}
+	for _, name := range sortedKeys(usages) {
+		if len(usages[name]) == 0 {
+			unused = append(unused, name)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "\naction %s (%s), used %d times:\n", name, d.Actions[name].Path, len(usages[name]))
+		for _, u := range usages[name] {
+			caller := fmt.Sprintf("workflow %s job %s", u.Workflow, u.Job)
+			if u.Action != "" {
+				caller = "action " + u.Action
+			}
+			step := fmt.Sprintf("step %d", u.StepIndex)
+			if u.Step.Id != "" {
+				step += fmt.Sprintf(" '%s'", u.Step.Id)
+			}
+			fmt.Fprintf(os.Stdout, "  %s %s (%s:%d)\n", caller, step, u.Path(d), u.Step.Line)
+			for _, input := range sortedKeys(u.Step.With) {
+				fmt.Fprintf(os.Stdout, "    %s: %s\n", input, strings.TrimSpace(u.Step.With[input]))
+			}
+		}
+	}
+	if len(unused) > 0 {
+		fmt.Fprintf(os.Stdout, "\n")
+	}
+	for _, name := range unused {
+		fmt.Fprintf(os.Stdout, "!!!! Action %s (%s) is not used\n", name, d.Actions[name].Path)
+	}
+	return 0
+}
+
+func graphHandler(c *broccli.CLI) int {
+	d := &dotgithub.DotGithub{
+		Path: c.Flag("path"),
+	}
+	err := d.InitFiles()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "!!!! Error with validation: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "!!!! Error with initialization: %s\n", err.Error())
+		return 1
+	}
+	for _, verr := range d.InitErrors() {
+		fmt.Fprintf(os.Stderr, "%s\n", verr)
+	}
+
+	g := graph.Build(d, graph.Options{WithoutSteps: c.Flag("without-steps") == "true"})
+	err = g.Write(os.Stdout, c.Flag("format"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "!!!! Error with writing graph: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+func triggersHandler(c *broccli.CLI) int {
+	d := &dotgithub.DotGithub{
+		Path:   c.Flag("path"),
+		Output: os.Stdout,
+	}
+	err := d.InitFiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "!!!! Error with initialization: %s\n", err.Error())
 		return 1
 	}
-	for _, verr := range validationErrors {
+	for _, verr := range d.InitErrors() {
 		fmt.Fprintf(os.Stdout, "%s\n", verr)
 	}
+
+	e := &trigger.Event{
+		Name:     c.Flag("event"),
+		Branch:   c.Flag("branch"),
+		Tag:      c.Flag("tag"),
+		Type:     c.Flag("type"),
+		Workflow: c.Flag("workflow"),
+		Files: strings.FieldsFunc(c.Flag("files"), func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\n'
+		}),
+	}
+	results := trigger.Simulate(d, e)
+	fmt.Fprintf(os.Stdout, "\nTriggered workflows:\n")
+	for _, r := range results {
+		if !r.Triggered {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "  %s\n", r.Workflow)
+		if len(r.NotChecked) > 0 {
+			fmt.Fprintf(os.Stdout, "    filters not checked: %s\n", strings.Join(r.NotChecked, ", "))
+		}
+		if c.Flag("jobs") != "true" {
+			continue
+		}
+		for _, j := range r.Jobs {
+			if j.Reason == "" {
+				continue
+			}
+			status := "unknown"
+			if j.Known && j.Runs {
+				status = "runs"
+			} else if j.Known {
+				status = "skipped"
+			}
+			fmt.Fprintf(os.Stdout, "    job %s: %s, %s\n", j.Name, status, j.Reason)
+		}
+	}
+	fmt.Fprintf(os.Stdout, "\nNot triggered workflows:\n")
+	for _, r := range results {
+		if !r.Triggered {
+			fmt.Fprintf(os.Stdout, "  %s: %s\n", r.Workflow, r.Reason)
+		}
+	}
 	return 0
 }
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func readNames(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read file %s: %w", path, err)
+	}
+	return strings.Fields(string(b)), nil
+}
@@ -1,14 +1,38 @@
 package action
 
 import (
+	"gopkg.in/yaml.v3"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
+// ActionRuns is the 'runs' field of an action.  Main, Pre, Post, PreIf and PostIf are for JavaScript actions,
+// Image, Args, Entrypoint, PreEntrypoint, PostEntrypoint and Env for Docker ones, and Steps for composite ones.
 type ActionRuns struct {
-	Using string        `yaml:"using"`
-	Steps []*ActionStep `yaml:"steps"`
+	Line           int               `yaml:"-"`
+	Using          string            `yaml:"using"`
+	Main           string            `yaml:"main"`
+	Pre            string            `yaml:"pre"`
+	Post           string            `yaml:"post"`
+	PreIf          string            `yaml:"pre-if"`
+	PostIf         string            `yaml:"post-if"`
+	Image          string            `yaml:"image"`
+	Args           []string          `yaml:"args"`
+	Entrypoint     string            `yaml:"entrypoint"`
+	PreEntrypoint  string            `yaml:"pre-entrypoint"`
+	PostEntrypoint string            `yaml:"post-entrypoint"`
+	Env            map[string]string `yaml:"env"`
+	Steps          []*ActionStep     `yaml:"steps"`
+}
+
+func (ar *ActionRuns) UnmarshalYAML(value *yaml.Node) error {
+	type plain ActionRuns
+	err := value.Decode((*plain)(ar))
+	if err != nil {
+		return err
+	}
+	ar.Line = value.Line
+	return nil
 }
 
 func (ar *ActionRuns) SetParentType(t string) {
@@ -26,6 +50,8 @@ func (ar *ActionRuns) IsStepExist(id string) bool {
 	return false
 }
 
+// IsStepOutputExist returns 0 when the step sets the output, 1 when it sets it with deprecated '::set-output'
+// command, -1 when there is no such step and -2 when the step does not have the output.
 func (ar *ActionRuns) IsStepOutputExist(step string, output string, d IDotGithub) int {
 	for _, s := range ar.Steps {
 		if s.Id != step {
@@ -33,14 +59,7 @@ func (ar *ActionRuns) IsStepOutputExist(step string, output string, d IDotGithub
 		}
 
 		if s.Uses == "" && s.Run != "" {
-			re := regexp.MustCompile(`echo[ ]+"([a-zA-Z0-9\-_]+)=.*"[ ]+.*>>[ ]+\$GITHUB_OUTPUT`)
-			found := re.FindAllSubmatch([]byte(s.Run), -1)
-			for _, f := range found {
-				if output == string(f[1]) {
-					return 0
-				}
-			}
-			return -2
+			return s.GetRunOutput(output)
 		}
 
 		re := regexp.MustCompile(`^\.\/\.github\/actions\/[a-z0-9\-]+$`)
@@ -73,21 +92,3 @@ func (ar *ActionRuns) IsStepOutputExist(step string, output string, d IDotGithub
 	}
 	return -1
 }
-
-func (ar *ActionRuns) Validate(dirName string, d IDotGithub) ([]string, error) {
-	var validationErrors []string
-	if ar.Steps != nil {
-		for i, s := range ar.Steps {
-			verrs, err := s.Validate(dirName, "", strconv.Itoa(i), d)
-			if err != nil {
-				return validationErrors, err
-			}
-			if len(verrs) > 0 {
-				for _, verr := range verrs {
-					validationErrors = append(validationErrors, verr)
-				}
-			}
-		}
-	}
-	return validationErrors, nil
-}
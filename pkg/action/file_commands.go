@@ -0,0 +1,267 @@
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// findFileCommandNames returns names written by the script to an environment file, eg. GITHUB_OUTPUT or
+// GITHUB_ENV, either as 'name=value' or as multiline 'name<<DELIMITER'.  In the parsed script f, text written to
+// the file by echo, printf, heredocs and 'tee', also from grouped commands like '{ ...; } >> file', is read as
+// the contents of the file.  Scripts that could not be parsed, which have nil f, and pwsh ones are checked line
+// by line.
+func findFileCommandNames(script string, file string, f *syntax.File) []string {
+	if f == nil || regexp.MustCompile(fmt.Sprintf(`\$env:%s([^a-zA-Z0-9_]|$)`, file)).MatchString(script) {
+		return findFileCommandNamesInLines(script, file)
+	}
+	w := &fileWriter{file: file}
+	w.stmts(f.Stmts, false)
+	return parseFileCommands(w.text.String())
+}
+
+// fileWriter collects, in order, the text that the script writes to the environment file.
+type fileWriter struct {
+	file string
+	text strings.Builder
+}
+
+func (w *fileWriter) stmts(stmts []*syntax.Stmt, toFile bool) {
+	for _, s := range stmts {
+		w.stmt(s, toFile)
+	}
+}
+
+// stmt collects text written by the statement when its standard output goes to the file.
+func (w *fileWriter) stmt(s *syntax.Stmt, toFile bool) {
+	if s == nil {
+		return
+	}
+	heredoc := ""
+	for _, r := range s.Redirs {
+		switch r.Op {
+		case syntax.RdrOut, syntax.AppOut, syntax.RdrAll, syntax.AppAll:
+			if r.N != nil && r.N.Value != "1" {
+				continue
+			}
+			toFile = w.isFile(r.Word)
+		case syntax.Hdoc, syntax.DashHdoc:
+			if r.Hdoc != nil {
+				heredoc = wordText(r.Hdoc)
+			}
+		}
+	}
+
+	switch c := s.Cmd.(type) {
+	case *syntax.CallExpr:
+		if toFile {
+			w.text.WriteString(commandOutput(c, heredoc))
+		} else if w.isTee(s) {
+			w.text.WriteString(heredoc)
+		}
+	case *syntax.BinaryCmd:
+		if c.Op == syntax.Pipe || c.Op == syntax.PipeAll {
+			w.stmt(c.X, w.isTee(c.Y))
+			w.stmt(c.Y, toFile)
+			return
+		}
+		w.stmt(c.X, toFile)
+		w.stmt(c.Y, toFile)
+	case *syntax.Block:
+		w.stmts(c.Stmts, toFile)
+	case *syntax.Subshell:
+		w.stmts(c.Stmts, toFile)
+	case *syntax.IfClause:
+		for ; c != nil; c = c.Else {
+			w.stmts(c.Then, toFile)
+		}
+	case *syntax.ForClause:
+		w.stmts(c.Do, toFile)
+	case *syntax.WhileClause:
+		w.stmts(c.Do, toFile)
+	case *syntax.CaseClause:
+		for _, item := range c.Items {
+			w.stmts(item.Stmts, toFile)
+		}
+	}
+}
+
+// isFile returns true when the word is exactly the variable with path to the file, eg. "$GITHUB_OUTPUT".
+func (w *fileWriter) isFile(word *syntax.Word) bool {
+	if word == nil || len(word.Parts) != 1 {
+		return false
+	}
+	part := word.Parts[0]
+	if dq, ok := part.(*syntax.DblQuoted); ok {
+		if len(dq.Parts) != 1 {
+			return false
+		}
+		part = dq.Parts[0]
+	}
+	p, ok := part.(*syntax.ParamExp)
+	return ok && p.Param != nil && p.Param.Value == w.file && p.Exp == nil && p.Index == nil && p.Repl == nil && p.Slice == nil && !p.Length && !p.Excl
+}
+
+// isTee returns true when the statement is 'tee' that writes to the file.
+func (w *fileWriter) isTee(s *syntax.Stmt) bool {
+	c, ok := s.Cmd.(*syntax.CallExpr)
+	if !ok || len(c.Args) == 0 || c.Args[0].Lit() != "tee" {
+		return false
+	}
+	for _, a := range c.Args[1:] {
+		if w.isFile(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandOutput returns text printed by echo or printf, or heredoc passed to cat.  Other commands print
+// nothing that can be known.
+func commandOutput(c *syntax.CallExpr, heredoc string) string {
+	if len(c.Args) == 0 {
+		return ""
+	}
+	args := make([]string, 0, len(c.Args)-1)
+	for _, a := range c.Args[1:] {
+		args = append(args, wordText(a))
+	}
+	switch c.Args[0].Lit() {
+	case "echo":
+		newline, escapes := "\n", false
+		for len(args) > 0 && regexp.MustCompile(`^-[neE]+$`).MatchString(args[0]) {
+			if strings.Contains(args[0], "n") {
+				newline = ""
+			}
+			escapes = strings.Contains(args[0], "e")
+			args = args[1:]
+		}
+		out := strings.Join(args, " ")
+		if escapes {
+			out = strings.ReplaceAll(out, `\n`, "\n")
+		}
+		return out + newline
+	case "printf":
+		if len(args) > 1 && args[0] == "-v" {
+			return ""
+		}
+		if len(args) == 0 {
+			return ""
+		}
+		return printfOutput(args[0], args[1:])
+	case "cat":
+		return heredoc
+	}
+	return ""
+}
+
+// printfOutput formats the arguments, repeating the format while there are arguments left like printf does.
+func printfOutput(format string, args []string) string {
+	format = strings.NewReplacer(`\n`, "\n", `\t`, "\t", "%%", "\x00").Replace(format)
+	re := regexp.MustCompile(`%[-+ #0-9.]*[a-zA-Z]`)
+	var out strings.Builder
+	for {
+		used := 0
+		out.WriteString(re.ReplaceAllStringFunc(format, func(string) string {
+			if used >= len(args) {
+				return ""
+			}
+			used++
+			return args[used-1]
+		}))
+		args = args[used:]
+		if used == 0 || len(args) == 0 {
+			break
+		}
+	}
+	return strings.ReplaceAll(out.String(), "\x00", "%")
+}
+
+// unknownText replaces parts of a word that are known only when the script runs, so they never form a name.
+const unknownText = "\x01"
+
+// wordText returns the value of the word, with expansions and substitutions replaced by unknownText.
+func wordText(w *syntax.Word) string {
+	var b strings.Builder
+	for _, part := range w.Parts {
+		wordPartText(&b, part)
+	}
+	return b.String()
+}
+
+func wordPartText(b *strings.Builder, part syntax.WordPart) {
+	switch p := part.(type) {
+	case *syntax.Lit:
+		b.WriteString(p.Value)
+	case *syntax.SglQuoted:
+		b.WriteString(p.Value)
+	case *syntax.DblQuoted:
+		for _, dp := range p.Parts {
+			wordPartText(b, dp)
+		}
+	default:
+		b.WriteString(unknownText)
+	}
+}
+
+// parseFileCommands returns names from the contents of an environment file.  Lines of a multiline value, up
+// to its delimiter, are skipped.
+func parseFileCommands(text string) []string {
+	names := []string{}
+	found := map[string]bool{}
+	re := regexp.MustCompile(`^[ \t]*([a-zA-Z_][a-zA-Z0-9_\-]*)(=|<<)(.*)$`)
+	delimiter := ""
+	for _, line := range strings.Split(text, "\n") {
+		if delimiter != "" {
+			if strings.TrimSpace(line) == delimiter {
+				delimiter = ""
+			}
+			continue
+		}
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if m[2] == "<<" {
+			delimiter = strings.TrimSpace(m[3])
+		}
+		if !found[m[1]] {
+			found[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// findFileCommandNamesInLines finds names in single lines that write to the file, with echo, printf, or pwsh
+// Out-File, Add-Content and Set-Content.
+func findFileCommandNamesInLines(script string, file string) []string {
+	target := fmt.Sprintf(`["']?(\$%s|\$\{%s\}|\$env:%s)([^a-zA-Z0-9_]|$)`, file, file, file)
+	name := `["']?([a-zA-Z_][a-zA-Z0-9_\-]*)(=|<<)`
+	reWriter := regexp.MustCompile(`(?:echo|printf|Write-Output)[ \t]+(?:-[a-zA-Z]+[ \t]+)*` + name)
+	rePwsh := regexp.MustCompile(`(?:^|\(|[ \t])["']([a-zA-Z_][a-zA-Z0-9_\-]*)(=|<<)[^|]*\|[ \t]*(Out-File|Add-Content|Set-Content)`)
+	rePwshValue := regexp.MustCompile(`-Value[ \t]+` + name)
+	reTarget := regexp.MustCompile(`(>>|>|tee[ \t]+(-a|--append)[ \t]+|-FilePath[ \t]+|-Path[ \t]+|Out-File[ \t]+|Add-Content[ \t]+|Set-Content[ \t]+)[ \t]*` + target)
+
+	names := []string{}
+	found := map[string]bool{}
+	add := func(n string) {
+		if !found[n] {
+			found[n] = true
+			names = append(names, n)
+		}
+	}
+	for _, line := range strings.Split(script, "\n") {
+		if !reTarget.MatchString(line) {
+			continue
+		}
+		for _, re := range []*regexp.Regexp{reWriter, rePwsh, rePwshValue} {
+			for _, f := range re.FindAllStringSubmatch(line, -1) {
+				add(f[1])
+			}
+		}
+	}
+	return names
+}
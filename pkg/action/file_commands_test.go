@@ -0,0 +1,51 @@
+package action
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindFileCommandNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"echo", `echo "a=1" >> $GITHUB_OUTPUT`, []string{"a"}},
+		{"echo unquoted braces", "echo b=2 >> \"${GITHUB_OUTPUT}\"", []string{"b"}},
+		{"echo with flag", `echo -n 'c=3' >> "$GITHUB_OUTPUT"`, []string{"c"}},
+		{"expression in value", `echo "d=${{ inputs.x }}" >> $GITHUB_OUTPUT`, []string{"d"}},
+		{"expression in name", `echo "${{ inputs.x }}=1" >> $GITHUB_OUTPUT`, []string{}},
+		{"variable in name", `echo "$name=1" >> $GITHUB_OUTPUT`, []string{}},
+		{"other file", `echo "a=1" >> $GITHUB_OUTPUT_FILE`, []string{}},
+		{"other env file", `echo "a=1" >> $GITHUB_ENV`, []string{}},
+		{"other redirect on the same line", `echo a=1 > /tmp/x; echo b=2 >> $GITHUB_OUTPUT`, []string{"b"}},
+		{"printf format", `printf 'e=%s\n' "$x" >> $GITHUB_OUTPUT`, []string{"e"}},
+		{"printf argument", `printf '%s\n' "f=$x" "g=1" >> $GITHUB_OUTPUT`, []string{"f", "g"}},
+		{"tee", `echo "h=1" | tee -a "$GITHUB_OUTPUT"`, []string{"h"}},
+		{"tee output piped", `echo "h=1" | tee -a "$GITHUB_OUTPUT" | grep h`, []string{"h"}},
+		{"pipe without tee", `echo "i=1" | sed s/i/j/ >> $GITHUB_OUTPUT`, []string{}},
+		{"group", "{\n  echo \"k=1\"\n  echo \"l<<EOF\"\n  echo \"a=b\"\n  echo EOF\n} >> \"$GITHUB_OUTPUT\"", []string{"k", "l"}},
+		{"subshell", `(echo m=1; echo n=2) >> $GITHUB_OUTPUT`, []string{"m", "n"}},
+		{"heredoc", "cat <<EOF >> $GITHUB_OUTPUT\no=1\nmulti<<DELIM\na\nb=2\nfoo\nDELIM\np=$x\nEOF\necho q=1", []string{"o", "multi", "p"}},
+		{"heredoc to tee", "tee -a $GITHUB_OUTPUT <<'EOF'\nr=1\nEOF", []string{"r"}},
+		{"multiline across commands", "echo 's<<EOF' >> $GITHUB_OUTPUT\necho 't=1' >> $GITHUB_OUTPUT\necho EOF >> $GITHUB_OUTPUT", []string{"s"}},
+		{"if", "if true; then\n  echo u=1 >> $GITHUB_OUTPUT\nelse\n  echo v=1 >> $GITHUB_OUTPUT\nfi", []string{"u", "v"}},
+		{"pwsh", `"w=1" | Out-File -FilePath $env:GITHUB_OUTPUT -Append`, []string{"w"}},
+		{"pwsh add content", `Add-Content -Path $env:GITHUB_OUTPUT -Value "x=1"`, []string{"x"}},
+		{"pwsh other file", `"w=1" | Out-File -FilePath $env:GITHUB_OUTPUT_X -Append`, []string{}},
+		{"syntax error", "echo \"y=1\" >> $GITHUB_OUTPUT\nif then", []string{"y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseShellScript(tt.script, false)
+			if err != nil {
+				f = nil
+			}
+			got := findFileCommandNames(tt.script, "GITHUB_OUTPUT", f)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
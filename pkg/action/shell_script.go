@@ -0,0 +1,42 @@
+package action
+
+import (
+	"regexp"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+var (
+	scriptExpression = regexp.MustCompile(`(?s)\${{.*?}}`)
+	scriptNameChar   = regexp.MustCompile(`[a-zA-Z0-9_]`)
+)
+
+// ReplaceExpressions replaces '${{ }}' expressions with placeholders of the same length, so that positions in
+// the script do not change.  Placeholder is made of zeros, which are valid in a word and in arithmetic, or of
+// '%' when it follows a name, as it would become a part of the name otherwise.
+func ReplaceExpressions(script string) string {
+	b := []byte(script)
+	for _, idx := range scriptExpression.FindAllStringIndex(script, -1) {
+		fill := byte('0')
+		if idx[0] > 0 && scriptNameChar.Match(b[idx[0]-1:idx[0]]) {
+			fill = '%'
+		}
+		for i := idx[0]; i < idx[1]; i++ {
+			if b[i] != '\n' {
+				b[i] = fill
+			}
+		}
+	}
+	return string(b)
+}
+
+// ParseShellScript parses a 'run' script as bash, or as POSIX shell when posix is true, with expressions
+// replaced by ReplaceExpressions.
+func ParseShellScript(script string, posix bool) (*syntax.File, error) {
+	variant := syntax.LangBash
+	if posix {
+		variant = syntax.LangPOSIX
+	}
+	return syntax.NewParser(syntax.Variant(variant)).Parse(strings.NewReader(ReplaceExpressions(script)), "")
+}
@@ -0,0 +1,25 @@
+package config
+
+// Actions is the policy of actions, reusable workflows and docker images that can be called with 'uses', like
+// the "allowed actions" setting of a GitHub organization.  When Allowed is not empty, only the matching ones can
+// be used.  Denied ones cannot be used even if they are allowed.  Entries are 'owner/repo' glob patterns or
+// 'docker://name' patterns for images, where name is without a tag.
+type Actions struct {
+	Allowed []string `yaml:"allowed"`
+	Denied  []string `yaml:"denied"`
+}
+
+func (a *Actions) Validate() error {
+	err := validateRepoPatterns(a.Allowed)
+	if err != nil {
+		return err
+	}
+	return validateRepoPatterns(a.Denied)
+}
+
+func (a *Actions) IsAllowed(repo string) bool {
+	if matchRepoPatterns(a.Denied, repo) {
+		return false
+	}
+	return len(a.Allowed) == 0 || matchRepoPatterns(a.Allowed, repo)
+}
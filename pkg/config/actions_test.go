@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestIsActionAllowed(t *testing.T) {
+	c := &Config{
+		Actions: &Actions{
+			Allowed: []string{"actions", "Cardinal-Cryptography/*", "docker://alpine", "docker://ghcr.io/*"},
+			Denied:  []string{"cardinal-cryptography/legacy", "docker://ghcr.io/evil"},
+		},
+	}
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{"actions/checkout", true},
+		{"cardinal-cryptography/workflows", true},
+		{"Cardinal-Cryptography/legacy", false},
+		{"someone/action", false},
+		{"docker://alpine", true},
+		{"docker://ghcr.io/owner", true},
+		{"docker://ghcr.io/evil", false},
+		{"docker://ubuntu", false},
+		{"docker://actions", false},
+	}
+	for _, tt := range tests {
+		if got := c.IsActionAllowed(tt.repo); got != tt.want {
+			t.Errorf("IsActionAllowed(%q) = %v, want %v", tt.repo, got, tt.want)
+		}
+	}
+	var empty *Config
+	if !empty.IsActionAllowed("someone/action") {
+		t.Errorf("IsActionAllowed on nil config = false, want true")
+	}
+}
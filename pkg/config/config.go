@@ -0,0 +1,95 @@
+// Package config contains configuration of the checks that is read from a YAML file.
+package config
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+)
+
+// Config is the contents of the configuration file.  Naming is a map of codes of the naming rules, eg. 'NA301',
+// to the convention that should be used instead of the default one.
+type Config struct {
+	Naming  map[string]*Naming `yaml:"naming"`
+	Pinning *Pinning           `yaml:"pinning"`
+	Actions *Actions           `yaml:"actions"`
+	Runners *Runners           `yaml:"runners"`
+}
+
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read file %s: %w", path, err)
+	}
+	c := &Config{}
+	err = yaml.Unmarshal(b, c)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal file %s: %w", path, err)
+	}
+	err = c.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid config in %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *Config) Validate() error {
+	for code, n := range c.Naming {
+		if !namingCodes[code] {
+			return fmt.Errorf("Naming of rule %s cannot be configured", code)
+		}
+		if n == nil {
+			return fmt.Errorf("Naming for %s is empty", code)
+		}
+		err := n.Validate()
+		if err != nil {
+			return fmt.Errorf("Naming for %s: %w", code, err)
+		}
+	}
+	if c.Pinning != nil {
+		err := c.Pinning.Validate()
+		if err != nil {
+			return fmt.Errorf("Pinning: %w", err)
+		}
+	}
+	if c.Actions != nil {
+		err := c.Actions.Validate()
+		if err != nil {
+			return fmt.Errorf("Actions: %w", err)
+		}
+	}
+	if c.Runners != nil {
+		err := c.Runners.Validate()
+		if err != nil {
+			return fmt.Errorf("Runners: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetNaming returns naming convention for a rule with the code, or the default one when it is not configured.
+func (c *Config) GetNaming(code string, defaultStyle string) *Naming {
+	if c != nil && c.Naming[code] != nil {
+		return c.Naming[code]
+	}
+	return &Naming{
+		Style: defaultStyle,
+	}
+}
+
+// IsUnpinnedAllowed returns true when actions from the 'owner/repo' repository do not have to be pinned to
+// a commit SHA.
+func (c *Config) IsUnpinnedAllowed(repo string) bool {
+	return c != nil && c.Pinning != nil && matchRepoPatterns(c.Pinning.AllowedOwners, repo)
+}
+
+// IsActionAllowed returns true when actions and reusable workflows from the 'owner/repo' repository, or the
+// 'docker://name' image, can be used.  Everything is allowed when there is no such configuration.
+func (c *Config) IsActionAllowed(repo string) bool {
+	return c == nil || c.Actions == nil || c.Actions.IsAllowed(repo)
+}
+
+// IsSelfHostedRunnerLabel returns true when the label is one of the configured labels of self-hosted runners.
+func (c *Config) IsSelfHostedRunnerLabel(label string) bool {
+	return c != nil && c.Runners != nil && c.Runners.IsLabel(label)
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	NamingKebab          = "kebab"
+	NamingSnake          = "snake"
+	NamingScreamingSnake = "SCREAMING_SNAKE"
+	NamingCamel          = "camel"
+)
+
+type namingStyle struct {
+	pattern     string
+	description string
+}
+
+var namingStyles = map[string]namingStyle{
+	NamingKebab: {
+		pattern:     `^[a-z0-9][a-z0-9\-]+$`,
+		description: "contain lowercase alphanumeric characters and hyphens only",
+	},
+	NamingSnake: {
+		pattern:     `^[a-z0-9][a-z0-9_]+$`,
+		description: "contain lowercase alphanumeric characters and underscore only",
+	},
+	NamingScreamingSnake: {
+		pattern:     `^[A-Z][A-Z0-9_]+$`,
+		description: "contain uppercase alphanumeric characters and underscore only",
+	},
+	NamingCamel: {
+		pattern:     `^[a-z][a-zA-Z0-9]+$`,
+		description: "be camelCase and contain alphanumeric characters only",
+	},
+}
+
+// namingCodes are codes of the rules which naming convention can be configured.
+var namingCodes = map[string]bool{
+	"NA101": true,
+	"NA301": true,
+	"NA501": true,
+	"NW101": true,
+	"NW103": true,
+	"NW107": true,
+	"NW301": true,
+	"NW501": true,
+	"NW502": true,
+	"NW701": true,
+}
+
+// Naming is a naming convention.  Style is one of the predefined ones: kebab, snake, SCREAMING_SNAKE or camel.
+// When Pattern is set, it is used instead of the style's one, and Style becomes just a human-readable name
+// of it, which does not have to be one of the predefined.
+type Naming struct {
+	Style   string `yaml:"style"`
+	Pattern string `yaml:"pattern"`
+}
+
+func (n *Naming) Validate() error {
+	if n.Pattern == "" {
+		if _, ok := namingStyles[n.Style]; !ok {
+			return fmt.Errorf("Style '%s' is invalid and pattern is missing", n.Style)
+		}
+		return nil
+	}
+	_, err := regexp.Compile(n.Pattern)
+	if err != nil {
+		return fmt.Errorf("Pattern '%s' is invalid: %w", n.Pattern, err)
+	}
+	return nil
+}
+
+func (n *Naming) Regexp() (*regexp.Regexp, error) {
+	if n.Pattern != "" {
+		return regexp.Compile(n.Pattern)
+	}
+	style, ok := namingStyles[n.Style]
+	if !ok {
+		return nil, fmt.Errorf("Style '%s' is invalid and pattern is missing", n.Style)
+	}
+	return regexp.Compile(style.pattern)
+}
+
+// Description returns what the name should be like, to be used after 'should', eg. 'contain lowercase
+// alphanumeric characters and hyphens only'.
+func (n *Naming) Description() string {
+	if n.Pattern != "" {
+		if n.Style != "" {
+			return fmt.Sprintf("be %s and match '%s'", n.Style, n.Pattern)
+		}
+		return fmt.Sprintf("match '%s'", n.Pattern)
+	}
+	style, ok := namingStyles[n.Style]
+	if ok {
+		return style.description
+	}
+	return "be " + n.Style
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestValidateNaming(t *testing.T) {
+	tests := []struct {
+		naming  map[string]*Naming
+		wantErr bool
+	}{
+		{map[string]*Naming{"NA301": {Style: NamingSnake}}, false},
+		{map[string]*Naming{"NW501": {Style: "kebab or snake", Pattern: `^[a-z][a-z0-9_\-]+$`}}, false},
+		{map[string]*Naming{"NW107": {Pattern: `^[a-z]+$`}}, false},
+		{map[string]*Naming{"NA301": {Style: "pascal"}}, true},
+		{map[string]*Naming{"NA301": {Pattern: `^[a-z`}}, true},
+		{map[string]*Naming{"NA301": nil}, true},
+		{map[string]*Naming{"EW201": {Style: NamingSnake}}, true},
+		{map[string]*Naming{"NW999": {Style: NamingSnake}}, true},
+	}
+	for _, tt := range tests {
+		err := (&Config{Naming: tt.naming}).Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%v): got error %v, want error %v", tt.naming, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNamingDescription(t *testing.T) {
+	tests := []struct {
+		naming *Naming
+		want   string
+	}{
+		{&Naming{Style: NamingKebab}, "contain lowercase alphanumeric characters and hyphens only"},
+		{&Naming{Style: NamingCamel}, "be camelCase and contain alphanumeric characters only"},
+		{&Naming{Style: "kebab or snake", Pattern: `^[a-z_\-]+$`}, `be kebab or snake and match '^[a-z_\-]+$'`},
+		{&Naming{Style: NamingSnake, Pattern: `^[a-z_]+$`}, `be snake and match '^[a-z_]+$'`},
+		{&Naming{Pattern: `^[a-z]+$`}, "match '^[a-z]+$'"},
+	}
+	for _, tt := range tests {
+		got := tt.naming.Description()
+		if got != tt.want {
+			t.Errorf("Description() of %+v: got %q, want %q", tt.naming, got, tt.want)
+		}
+	}
+}
+
+func TestGetNaming(t *testing.T) {
+	var c *Config
+	if got := c.GetNaming("NA301", NamingKebab); got.Style != NamingKebab {
+		t.Errorf("GetNaming on nil config: got style %q, want %q", got.Style, NamingKebab)
+	}
+	c = &Config{Naming: map[string]*Naming{"NA301": {Style: NamingSnake}}}
+	if got := c.GetNaming("NA301", NamingKebab); got.Style != NamingSnake {
+		t.Errorf("GetNaming of configured code: got style %q, want %q", got.Style, NamingSnake)
+	}
+	if got := c.GetNaming("NA501", NamingKebab); got.Style != NamingKebab {
+		t.Errorf("GetNaming of other code: got style %q, want %q", got.Style, NamingKebab)
+	}
+}
@@ -0,0 +1,12 @@
+package config
+
+// Pinning configures the check of external actions being pinned to a full commit SHA.  Actions and reusable
+// workflows from repositories matching AllowedOwners can be pinned to a tag or a branch, eg. 'actions/*' or
+// own organization.
+type Pinning struct {
+	AllowedOwners []string `yaml:"allowed-owners"`
+}
+
+func (p *Pinning) Validate() error {
+	return validateRepoPatterns(p.AllowedOwners)
+}
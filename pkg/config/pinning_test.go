@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestIsUnpinnedAllowed(t *testing.T) {
+	c := &Config{
+		Pinning: &Pinning{
+			AllowedOwners: []string{"actions/*", "Cardinal-Cryptography", "someone/action"},
+		},
+	}
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{"actions/checkout", true},
+		{"Actions/Checkout", true},
+		{"cardinal-cryptography/workflows", true},
+		{"someone/action", true},
+		{"someone/other", false},
+		{"actions-fork/checkout", false},
+		{"docker://actions/image", false},
+	}
+	for _, tt := range tests {
+		if got := c.IsUnpinnedAllowed(tt.repo); got != tt.want {
+			t.Errorf("IsUnpinnedAllowed(%q) = %v, want %v", tt.repo, got, tt.want)
+		}
+	}
+	var empty *Config
+	if empty.IsUnpinnedAllowed("actions/checkout") {
+		t.Errorf("IsUnpinnedAllowed on nil config = true, want false")
+	}
+}
+
+func TestValidatePinning(t *testing.T) {
+	for _, owners := range [][]string{{""}, {"actions/["}} {
+		err := (&Config{Pinning: &Pinning{AllowedOwners: owners}}).Validate()
+		if err == nil {
+			t.Errorf("Validate(%q): got no error", owners)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const dockerPrefix = "docker://"
+
+// validateRepoPatterns checks patterns of 'owner/repo' that are used in lists of repositories.
+func validateRepoPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if p == "" {
+			return fmt.Errorf("Repository pattern is empty")
+		}
+		_, err := path.Match(p, "")
+		if err != nil {
+			return fmt.Errorf("Repository pattern '%s' is invalid: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// matchRepoPatterns returns true when 'owner/repo' matches any of the glob patterns, eg. 'actions/*'.  Pattern
+// without a slash is an owner and matches all its repositories.  Docker images are passed as 'docker://name'
+// and are matched only with patterns starting with 'docker://'.  Matching is case-insensitive like GitHub's.
+func matchRepoPatterns(patterns []string, repo string) bool {
+	repo = strings.ToLower(repo)
+	isDocker := strings.HasPrefix(repo, dockerPrefix)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if isDocker != strings.HasPrefix(p, dockerPrefix) {
+			continue
+		}
+		if !isDocker && !strings.Contains(p, "/") {
+			p = p + "/*"
+		}
+		m, _ := path.Match(p, repo)
+		if m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Runners lists labels of self-hosted runners that jobs can use in 'runs-on', in addition to GitHub-hosted
+// runners.  Entries can be glob patterns, eg. 'gpu-*'.
+type Runners struct {
+	Labels []string `yaml:"labels"`
+}
+
+func (r *Runners) Validate() error {
+	for _, l := range r.Labels {
+		if l == "" {
+			return fmt.Errorf("Runner label is empty")
+		}
+		_, err := path.Match(l, "")
+		if err != nil {
+			return fmt.Errorf("Runner label pattern '%s' is invalid: %w", l, err)
+		}
+	}
+	return nil
+}
+
+// IsLabel returns true when the label matches any of the patterns.  Labels are case-insensitive.
+func (r *Runners) IsLabel(label string) bool {
+	for _, l := range r.Labels {
+		m, _ := path.Match(strings.ToLower(l), strings.ToLower(label))
+		if m {
+			return true
+		}
+	}
+	return false
+}
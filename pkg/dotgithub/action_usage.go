@@ -0,0 +1,74 @@
+package dotgithub
+
+import (
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+)
+
+// ActionUsage is a step that calls a local action.  It is either a step of workflow's job, when Workflow and
+// Job are set, or a step of another composite action, when Action is set.  StepIndex starts from 0.
+type ActionUsage struct {
+	Workflow  string
+	Job       string
+	Action    string
+	StepIndex int
+	Step      *action.ActionStep
+}
+
+// Path returns path of the file containing the step.
+func (u *ActionUsage) Path(d *DotGithub) string {
+	if u.Action != "" {
+		return d.Actions[u.Action].Path
+	}
+	return d.Workflows[u.Workflow].Path
+}
+
+// Raw returns contents of the file containing the step.
+func (u *ActionUsage) Raw(d *DotGithub) []byte {
+	if u.Action != "" {
+		return d.Actions[u.Action].Raw
+	}
+	return d.Workflows[u.Workflow].Raw
+}
+
+// GetActionUsages returns a reverse index of local actions: for each of them, steps of workflows and other
+// actions that call it, sorted by file, job and step.  Actions that are never used have an empty list.
+func (d *DotGithub) GetActionUsages() map[string][]*ActionUsage {
+	usages := map[string][]*ActionUsage{}
+	for name := range d.Actions {
+		usages[name] = []*ActionUsage{}
+	}
+	add := func(u *ActionUsage) {
+		name := GetLocalActionName(u.Step.Uses)
+		if _, ok := usages[name]; ok {
+			usages[name] = append(usages[name], u)
+		}
+	}
+	for _, wName := range sortedKeys(d.Workflows) {
+		w := d.Workflows[wName]
+		if w == nil {
+			continue
+		}
+		for _, jName := range sortedKeys(w.Jobs) {
+			if w.Jobs[jName] == nil {
+				continue
+			}
+			for i, s := range w.Jobs[jName].Steps {
+				if s != nil {
+					add(&ActionUsage{Workflow: wName, Job: jName, StepIndex: i, Step: s})
+				}
+			}
+		}
+	}
+	for _, aName := range sortedKeys(d.Actions) {
+		a := d.Actions[aName]
+		if a == nil || a.Runs == nil {
+			continue
+		}
+		for i, s := range a.Runs.Steps {
+			if s != nil {
+				add(&ActionUsage{Action: aName, StepIndex: i, Step: s})
+			}
+		}
+	}
+	return usages
+}
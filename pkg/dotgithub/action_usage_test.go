@@ -0,0 +1,57 @@
+package dotgithub
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetLocalActionName(t *testing.T) {
+	tests := []struct {
+		uses string
+		want string
+	}{
+		{"./.github/actions/build", "build"},
+		{"./.github/actions/build/", "build"},
+		{"./.github/actions/parent/child", "parent/child"},
+		{"./.github/actions/", ""},
+		{"./.github/workflows/build.yml", ""},
+		{"./actions/build", ""},
+		{"actions/checkout@v4", ""},
+		{"docker://alpine:3", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := GetLocalActionName(tt.uses); got != tt.want {
+			t.Errorf("GetLocalActionName(%q) = %q, want %q", tt.uses, got, tt.want)
+		}
+	}
+}
+
+func TestGetActionUsages(t *testing.T) {
+	d := &DotGithub{
+		Path: filepath.Join("testdata", "usage", ".github"),
+	}
+	err := d.InitFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.InitErrors()) > 0 {
+		t.Fatal(d.InitErrors()[0])
+	}
+	got := map[string][]string{}
+	for name, usages := range d.GetActionUsages() {
+		got[name] = []string{}
+		for _, u := range usages {
+			got[name] = append(got[name], fmt.Sprintf("%s%s/%s#%d", u.Workflow, u.Action, u.Job, u.StepIndex))
+		}
+	}
+	want := map[string][]string{
+		"parent":       {"main.yml/main#2"},
+		"parent/child": {"main.yml/main#1", "parent/#0"},
+		"unused":       {},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -1,19 +1,33 @@
 package dotgithub
 
 import (
+	"errors"
 	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
 	"io/ioutil"
-	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
 	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
 )
 
+// Resolver returns contents of the action.yml file of an external action, eg. 'actions/checkout@v4'.  When the
+// action does not exist, it should return nil without an error.
+type Resolver interface {
+	Resolve(path string) ([]byte, error)
+}
+
+// DotGithub is the parsed .github directory.  Vars and Secrets can be set directly instead of reading them from
+// VarsFile and SecretsFile - when nil, the called variables and secrets are not checked.  Progress messages are
+// written to Output, if set, and external actions are fetched with Resolver, if set.
 type DotGithub struct {
 	Path            string
 	VarsFile        string
@@ -23,9 +37,16 @@ type DotGithub struct {
 	Actions         map[string]*action.Action
 	ExternalActions map[string]*action.Action
 	Workflows       map[string]*workflow.Workflow
+	Output          io.Writer
+	Resolver        Resolver
+	initErrors      []*finding.Finding
 }
 
+// InitFiles reads and parses all the actions and workflows.  Files that cannot be read or parsed do not stop
+// the initialization - they are returned as findings by InitErrors and are left out of the validation.
+// Error is returned only when list of variables or secrets cannot be read.
 func (d *DotGithub) InitFiles() error {
+	d.initErrors = []*finding.Finding{}
 	if d.Path == "" {
 		return nil
 	}
@@ -33,66 +54,106 @@ func (d *DotGithub) InitFiles() error {
 	d.getActions()
 	d.getWorkflows()
 
-	for _, a := range d.Actions {
+	for _, n := range sortedKeys(d.Actions) {
+		a := d.Actions[n]
+		d.logf("**** Reading %s ...\n", a.Path)
 		err := a.Init(false)
 		if err != nil {
-			return err
+			d.appendInitErrors(a.Path, "action "+a.DirName, "EA", err)
+			delete(d.Actions, n)
 		}
 	}
-	for _, w := range d.Workflows {
+	for _, n := range sortedKeys(d.Workflows) {
+		w := d.Workflows[n]
+		d.logf("**** Reading %s ...\n", w.Path)
 		err := w.Init()
 		if err != nil {
-			return err
+			d.appendInitErrors(w.Path, "workflow "+n, "EW", err)
+			delete(d.Workflows, n)
 		}
 	}
 
-	d.getVars()
-	d.getSecrets()
+	err := d.getVars()
+	if err != nil {
+		return err
+	}
+	err = d.getSecrets()
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (d *DotGithub) DownloadExternalAction(path string) error {
-	if d.ExternalActions == nil {
-		d.ExternalActions = map[string]*action.Action{}
+func (d *DotGithub) logf(format string, a ...interface{}) {
+	if d.Output != nil {
+		fmt.Fprintf(d.Output, format, a...)
 	}
-	if d.ExternalActions[path] != nil {
-		return nil
+}
+
+func (d *DotGithub) appendInitError(path string, name string, code string, line int, desc string) {
+	d.initErrors = append(d.initErrors, &finding.Finding{
+		Code:        code,
+		Severity:    finding.SeverityError,
+		File:        path,
+		Line:        line,
+		Name:        name,
+		Description: desc,
+	})
+}
+
+// appendInitErrors turns an error from reading or unmarshalling a file into findings.  Every line reported by
+// the YAML decoder becomes a separate finding with code ending with 001, and failure to read the file is 002.
+func (d *DotGithub) appendInitErrors(path string, name string, codePrefix string, err error) {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		d.appendInitError(path, name, codePrefix+"002", 0, fmt.Sprintf("Cannot read file: %s", pathErr.Err.Error()))
+		return
 	}
 
-	repoVersion := strings.Split(path, "@")
-	ownerRepoDir := strings.SplitN(repoVersion[0], "/", 3)
-	directory := ""
-	if len(ownerRepoDir) > 2 {
-		directory = "/" + ownerRepoDir[2]
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		for _, e := range typeErr.Errors {
+			line, msg := splitYAMLErrorLine(e)
+			d.appendInitError(path, name, codePrefix+"001", line, fmt.Sprintf("Cannot parse YAML at line %d: %s", line, msg))
+		}
+		return
 	}
-	actionURLPrefix := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", ownerRepoDir[0], ownerRepoDir[1], repoVersion[1])
 
-	req, err := http.NewRequest("GET", actionURLPrefix+directory+"/action.yml", strings.NewReader(""))
-	if err != nil {
-		return err
+	cause := err
+	if errors.Unwrap(err) != nil {
+		cause = errors.Unwrap(err)
+	}
+	line, msg := splitYAMLErrorLine(strings.TrimPrefix(cause.Error(), "yaml: "))
+	d.appendInitError(path, name, codePrefix+"001", line, fmt.Sprintf("Cannot parse YAML at line %d: %s", line, msg))
+}
+
+func splitYAMLErrorLine(s string) (int, string) {
+	re := regexp.MustCompile(`^line ([0-9]+): (.*)$`)
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return 0, s
+	}
+	line, _ := strconv.Atoi(m[1])
+	return line, m[2]
+}
+
+func (d *DotGithub) DownloadExternalAction(path string) error {
+	if d.ExternalActions == nil {
+		d.ExternalActions = map[string]*action.Action{}
+	}
+	if _, ok := d.ExternalActions[path]; ok || d.Resolver == nil {
+		return nil
 	}
-	c := &http.Client{}
-	resp, err := c.Do(req)
 
+	b, err := d.Resolver.Resolve(path)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != 200 {
-		req, err = http.NewRequest("GET", actionURLPrefix+directory+"/action.yaml", strings.NewReader(""))
-		if err != nil {
-			return err
-		}
-		resp, err = c.Do(req)
-		if err != nil {
-			return err
-		}
-		if resp.StatusCode != 200 {
-			return nil
-		}
+	if b == nil {
+		d.ExternalActions[path] = nil
+		return nil
 	}
-	defer resp.Body.Close()
-	b, _ := ioutil.ReadAll(resp.Body)
 
 	d.ExternalActions[path] = &action.Action{
 		Path:    path,
@@ -111,10 +172,10 @@ func (d *DotGithub) getActions() {
 	actionsPath := filepath.Join(d.Path, "actions")
 	entries, err := os.ReadDir(actionsPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return
+		if !os.IsNotExist(err) {
+			d.appendInitError(actionsPath, "actions", "EA002", 0, fmt.Sprintf("Cannot read directory: %s", err.Error()))
 		}
-		log.Fatal(err)
+		return
 	}
 
 	d.getActionsFromEntries(actionsPath, entries, "")
@@ -123,13 +184,16 @@ func (d *DotGithub) getActions() {
 func (d *DotGithub) getActionsFromEntries(actionsPath string, entries []os.DirEntry, parentDir string) {
 	for _, e := range entries {
 		entryPath := filepath.Join(actionsPath, e.Name())
+		actionName := e.Name()
 		if parentDir != "" {
 			entryPath = filepath.Join(actionsPath, parentDir, e.Name())
+			actionName = parentDir + "/" + e.Name()
 		}
 
 		fileInfo, err := os.Stat(entryPath)
 		if err != nil {
-			log.Fatal(err)
+			d.appendInitError(entryPath, "action "+actionName, "EA002", 0, fmt.Sprintf("Cannot read directory: %s", err.Error()))
+			continue
 		}
 		if !fileInfo.IsDir() {
 			continue
@@ -138,7 +202,8 @@ func (d *DotGithub) getActionsFromEntries(actionsPath string, entries []os.DirEn
 		if parentDir == "" {
 			entries2, err2 := os.ReadDir(entryPath)
 			if err2 != nil {
-				log.Fatal(err)
+				d.appendInitError(entryPath, "action "+actionName, "EA002", 0, fmt.Sprintf("Cannot read directory: %s", err2.Error()))
+				continue
 			}
 			d.getActionsFromEntries(actionsPath, entries2, e.Name())
 		}
@@ -147,14 +212,16 @@ func (d *DotGithub) getActionsFromEntries(actionsPath string, entries []os.DirEn
 		_, err = os.Stat(actionYMLPath)
 		ymlNotFound := os.IsNotExist(err)
 		if err != nil && !ymlNotFound {
-			log.Fatal(err)
+			d.appendInitError(actionYMLPath, "action "+actionName, "EA002", 0, fmt.Sprintf("Cannot read file: %s", err.Error()))
+			continue
 		}
 		if ymlNotFound {
 			actionYAMLPath := filepath.Join(entryPath, "action.yaml")
 			_, err = os.Stat(actionYAMLPath)
 			yamlNotFound := os.IsNotExist(err)
 			if err != nil && !yamlNotFound {
-				log.Fatal(err)
+				d.appendInitError(actionYAMLPath, "action "+actionName, "EA002", 0, fmt.Sprintf("Cannot read file: %s", err.Error()))
+				continue
 			}
 			if !yamlNotFound {
 				actionYMLPath = actionYAMLPath
@@ -162,10 +229,6 @@ func (d *DotGithub) getActionsFromEntries(actionsPath string, entries []os.DirEn
 				continue
 			}
 		}
-		actionName := e.Name()
-		if parentDir != "" {
-			actionName = parentDir + "/" + e.Name()
-		}
 		d.Actions[actionName] = &action.Action{
 			Path:    actionYMLPath,
 			DirName: actionName,
@@ -178,21 +241,22 @@ func (d *DotGithub) getWorkflows() {
 	workflowsPath := filepath.Join(d.Path, "workflows")
 	entries, err := os.ReadDir(workflowsPath)
 	if err != nil {
-		log.Fatal(err)
+		// Unlike the actions directory, the workflows one is expected in every .github directory, so it is reported
+		// even when it is missing.
+		d.appendInitError(workflowsPath, "workflows", "EW002", 0, fmt.Sprintf("Cannot read directory: %s", err.Error()))
+		return
 	}
+	re := regexp.MustCompile("\\.y[a]{0,1}ml$")
 	for _, e := range entries {
-		m, err := regexp.MatchString("\\.y[a]{0,1}ml$", e.Name())
-		if err != nil {
-			log.Fatal(err)
-		}
-		if !m {
+		if !re.MatchString(e.Name()) {
 			continue
 		}
 
 		entryPath := filepath.Join(workflowsPath, e.Name())
 		fileInfo, err := os.Stat(entryPath)
 		if err != nil {
-			log.Fatal(err)
+			d.appendInitError(entryPath, "workflow "+e.Name(), "EW002", 0, fmt.Sprintf("Cannot read file: %s", err.Error()))
+			continue
 		}
 		if !fileInfo.Mode().IsRegular() {
 			continue
@@ -203,88 +267,51 @@ func (d *DotGithub) getWorkflows() {
 	}
 }
 
-func (d *DotGithub) getVars() {
-	d.Vars = make(map[string]bool)
+func (d *DotGithub) getVars() error {
 	if d.VarsFile != "" {
-		fmt.Fprintf(os.Stdout, "**** Reading file with list of possible variable names %s ...\n", d.VarsFile)
+		d.Vars = make(map[string]bool)
+		d.logf("**** Reading file with list of possible variable names %s ...\n", d.VarsFile)
 		b, err := ioutil.ReadFile(d.VarsFile)
 		if err != nil {
-			log.Fatal(fmt.Errorf("Cannot read file %s: %w", d.VarsFile, err))
+			return fmt.Errorf("Cannot read file %s: %w", d.VarsFile, err)
 		}
 		l := strings.Fields(string(b))
 		for _, v := range l {
 			d.Vars[v] = true
 		}
 	}
+	return nil
 }
 
-func (d *DotGithub) getSecrets() {
-	d.Secrets = make(map[string]bool)
+func (d *DotGithub) getSecrets() error {
 	if d.SecretsFile != "" {
-		fmt.Fprintf(os.Stdout, "**** Reading file with list of possible secret names %s ...\n", d.SecretsFile)
+		d.Secrets = make(map[string]bool)
+		d.logf("**** Reading file with list of possible secret names %s ...\n", d.SecretsFile)
 		b, err := ioutil.ReadFile(d.SecretsFile)
 		if err != nil {
-			log.Fatal(fmt.Errorf("Cannot read file %s: %w", d.SecretsFile, err))
+			return fmt.Errorf("Cannot read file %s: %w", d.SecretsFile, err)
 		}
 		l := strings.Fields(string(b))
 		for _, s := range l {
 			d.Secrets[s] = true
 		}
 	}
+	return nil
 }
 
-func (d *DotGithub) validateActions() ([]string, error) {
-	var validationErrors []string
-	for _, a := range d.Actions {
-		verrs, err := a.Validate(d)
-		if err != nil {
-			return validationErrors, err
-		}
-		if len(verrs) > 0 {
-			for _, verr := range verrs {
-				validationErrors = append(validationErrors, verr)
-			}
-		}
-	}
-	return validationErrors, nil
-}
-
-func (d *DotGithub) validateWorkflows() ([]string, error) {
-	var validationErrors []string
-	for _, w := range d.Workflows {
-		verrs, err := w.Validate(d)
-		if err != nil {
-			return validationErrors, err
-		}
-		if len(verrs) > 0 {
-			for _, verr := range verrs {
-				validationErrors = append(validationErrors, verr)
-			}
-		}
-	}
-	return validationErrors, nil
+// InitErrors returns findings for files that could not be read or parsed by InitFiles.
+func (d *DotGithub) InitErrors() []*finding.Finding {
+	return d.initErrors
 }
 
-func (d *DotGithub) Validate() ([]string, error) {
-	var validationErrors []string
-
-	verrs, err := d.validateActions()
-	if err != nil {
-		return validationErrors, err
-	}
-	for _, v := range verrs {
-		validationErrors = append(validationErrors, v)
-	}
-
-	verrs, err = d.validateWorkflows()
-	if err != nil {
-		return validationErrors, err
-	}
-	for _, v := range verrs {
-		validationErrors = append(validationErrors, v)
+// GetLocalActionName returns name of the local action from the 'uses' field of a step, or empty string when
+// the step does not call an action from .github/actions directory.  Name of a nested action contains its
+// parent directory, eg. 'parent/child', the same as its key in DotGithub.Actions.
+func GetLocalActionName(uses string) string {
+	if !strings.HasPrefix(uses, "./.github/actions/") {
+		return ""
 	}
-
-	return validationErrors, nil
+	return strings.TrimSuffix(strings.TrimPrefix(uses, "./.github/actions/"), "/")
 }
 
 func (d *DotGithub) GetAction(n string) *action.Action {
@@ -297,7 +324,7 @@ func (d *DotGithub) GetExternalAction(n string) *action.Action {
 
 func (d *DotGithub) IsWorkflowJobStepOutputExist(action string, job string, step string, output string) bool {
 	if d.Workflows[action] != nil && d.Workflows[action].Jobs[job] != nil {
-		if d.Workflows[action].Jobs[job].IsStepOutputExist(step, output, d) == 0 {
+		if d.Workflows[action].Jobs[job].IsStepOutputExist(step, output, d) >= 0 {
 			return true
 		}
 	}
@@ -314,17 +341,11 @@ func (d *DotGithub) IsEnvExistInWorkflowOrItsJob(action string, job string, env
 }
 
 func (d *DotGithub) IsVarsFileExist() bool {
-	if d.VarsFile != "" {
-		return true
-	}
-	return false
+	return d.Vars != nil
 }
 
 func (d *DotGithub) IsSecretsFileExist() bool {
-	if d.SecretsFile != "" {
-		return true
-	}
-	return false
+	return d.Secrets != nil
 }
 
 func (d *DotGithub) IsVarExist(n string) bool {
@@ -334,3 +355,12 @@ func (d *DotGithub) IsVarExist(n string) bool {
 func (d *DotGithub) IsSecretExist(n string) bool {
 	return d.Secrets[n]
 }
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
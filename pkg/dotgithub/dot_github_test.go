@@ -0,0 +1,42 @@
+package dotgithub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInitFilesMissingDirectories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".github")
+	err := os.Mkdir(path, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &DotGithub{
+		Path: path,
+	}
+	err = d.InitFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Missing actions directory is fine, but missing workflows one is reported.
+	initErrors := d.InitErrors()
+	if len(initErrors) != 1 {
+		t.Fatalf("got %d init errors, want 1: %v", len(initErrors), initErrors)
+	}
+	if initErrors[0].Code != "EW002" || initErrors[0].File != filepath.Join(path, "workflows") {
+		t.Errorf("got %s in %s, want EW002 in workflows directory", initErrors[0].Code, initErrors[0].File)
+	}
+
+	err = os.Mkdir(filepath.Join(path, "workflows"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.InitFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.InitErrors()) != 0 {
+		t.Errorf("got init errors %v, want none", d.InitErrors())
+	}
+}
@@ -0,0 +1,72 @@
+package expr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Condition is a parsed 'if' field.  When the field has text around '${{ }}', eg. '${{ x }} && y', the whole
+// field is a string and Node is nil.
+type Condition struct {
+	Node         Node
+	IsStringOnly bool
+}
+
+var wrappedExpression = regexp.MustCompile(`(?s)^\$\{\{(.*)\}\}$`)
+
+// ParseCondition parses an 'if' field, which is an expression with or without the surrounding '${{' and '}}'.
+func ParseCondition(s string) (*Condition, error) {
+	s = strings.TrimSpace(s)
+	m := wrappedExpression.FindStringSubmatch(s)
+	if m != nil && !strings.Contains(m[1], "}}") {
+		s = m[1]
+	} else if strings.Contains(s, "${{") {
+		return &Condition{IsStringOnly: true}, nil
+	}
+	n, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Condition{Node: n}, nil
+}
+
+// Eval returns the result of the condition, and false as the second value when it cannot be known.  GitHub
+// adds 'success() &&' to conditions that do not call any status function, so such conditions are Unknown in
+// jobs and steps that can be affected by a failure, unless CanFail is false.
+func (c *Condition) Eval(values Values, canFail bool) (bool, bool) {
+	if c.IsStringOnly {
+		return true, true
+	}
+	result, known := IsTruthy(Eval(c.Node, values))
+	if !known {
+		return false, false
+	}
+	if canFail && !c.HasStatusFunction() && result {
+		return false, false
+	}
+	return result, true
+}
+
+// HasStatusFunction returns true when the condition calls success(), failure(), cancelled() or always().
+func (c *Condition) HasStatusFunction() bool {
+	return c.Node != nil && len(StatusFunctions(c.Node)) > 0
+}
+
+// StatusFunctions returns names of status functions called in the expression.
+func StatusFunctions(n Node) []string {
+	var names []string
+	Walk(n, func(n Node) {
+		if c, ok := n.(*Call); ok && IsStatusFunction(c.Name) {
+			names = append(names, c.Name)
+		}
+	})
+	return names
+}
+
+func IsStatusFunction(name string) bool {
+	switch strings.ToLower(name) {
+	case "success", "failure", "cancelled", "always":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package expr
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type unknown struct{}
+
+// Unknown is the value of an expression that cannot be evaluated statically.
+var Unknown interface{} = unknown{}
+
+// Values are the known values of context properties, with lowercase dotted paths as keys, eg.
+// 'github.event_name'.  Properties that are not there evaluate to Unknown.  Results of status functions can be
+// set with keys like 'success()'.
+type Values map[string]interface{}
+
+// Eval evaluates the parsed expression.  Result is nil, bool, float64, string or Unknown.  Status functions
+// evaluate to Unknown, except always() which is true, unless their result is in values.
+func Eval(n Node, values Values) interface{} {
+	switch v := n.(type) {
+	case *Literal:
+		return v.Value
+	case *Context, *Property, *Index:
+		path, ok := propertyPath(n)
+		if !ok {
+			return Unknown
+		}
+		if value, ok := values[path]; ok {
+			return value
+		}
+		return Unknown
+	case *Not:
+		t, known := IsTruthy(Eval(v.Operand, values))
+		if !known {
+			return Unknown
+		}
+		return !t
+	case *Binary:
+		return evalBinary(v, values)
+	case *Call:
+		return evalCall(v, values)
+	}
+	return Unknown
+}
+
+func evalBinary(b *Binary, values Values) interface{} {
+	left := Eval(b.Left, values)
+	switch b.Operator {
+	case "&&", "||":
+		t, known := IsTruthy(left)
+		if !known {
+			// Result is still known when both operands give the same truthiness, eg. 'x && false'.
+			right := Eval(b.Right, values)
+			rt, rknown := IsTruthy(right)
+			if rknown && rt == (b.Operator == "||") {
+				return rt
+			}
+			return Unknown
+		}
+		if t == (b.Operator == "||") {
+			return left
+		}
+		return Eval(b.Right, values)
+	}
+	right := Eval(b.Right, values)
+	if left == Unknown || right == Unknown {
+		return Unknown
+	}
+	switch b.Operator {
+	case "==":
+		return isEqual(left, right)
+	case "!=":
+		return !isEqual(left, right)
+	}
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	var c int
+	if lok && rok {
+		c = strings.Compare(strings.ToLower(ls), strings.ToLower(rs))
+	} else {
+		ln, rn := toNumber(left), toNumber(right)
+		if math.IsNaN(ln) || math.IsNaN(rn) {
+			return false
+		}
+		switch {
+		case ln < rn:
+			c = -1
+		case ln > rn:
+			c = 1
+		}
+	}
+	switch b.Operator {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func evalCall(c *Call, values Values) interface{} {
+	args := make([]interface{}, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = Eval(a, values)
+		if args[i] == Unknown {
+			return Unknown
+		}
+	}
+	if IsStatusFunction(c.Name) {
+		if value, ok := values[c.Name+"()"]; ok {
+			return value
+		}
+	}
+	switch c.Name {
+	case "always":
+		return true
+	case "contains", "startswith", "endswith":
+		if len(args) != 2 {
+			return Unknown
+		}
+		if _, ok := args[0].(string); !ok && c.Name == "contains" {
+			// Searching in an array, which is never a literal.
+			return Unknown
+		}
+		s, item := strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1]))
+		switch c.Name {
+		case "contains":
+			return strings.Contains(s, item)
+		case "startswith":
+			return strings.HasPrefix(s, item)
+		}
+		return strings.HasSuffix(s, item)
+	case "format":
+		if len(args) == 0 {
+			return Unknown
+		}
+		return format(toString(args[0]), args[1:])
+	}
+	return Unknown
+}
+
+func format(f string, args []interface{}) interface{} {
+	re := regexp.MustCompile(`\{\{|\}\}|\{([0-9]+)\}`)
+	return re.ReplaceAllStringFunc(f, func(m string) string {
+		switch m {
+		case "{{":
+			return "{"
+		case "}}":
+			return "}"
+		}
+		i, _ := strconv.Atoi(m[1 : len(m)-1])
+		if i >= len(args) {
+			return m
+		}
+		return toString(args[i])
+	})
+}
+
+// propertyPath returns lowercase dotted path of a context property, eg. 'github.event.action'.
+func propertyPath(n Node) (string, bool) {
+	switch v := n.(type) {
+	case *Context:
+		return v.Name, true
+	case *Property:
+		p, ok := propertyPath(v.Object)
+		if !ok || v.Name == "*" {
+			return "", false
+		}
+		return p + "." + strings.ToLower(v.Name), true
+	case *Index:
+		p, ok := propertyPath(v.Object)
+		l, isLiteral := v.Index.(*Literal)
+		if !ok || !isLiteral {
+			return "", false
+		}
+		s, isString := l.Value.(string)
+		if !isString {
+			return "", false
+		}
+		return p + "." + strings.ToLower(s), true
+	}
+	return "", false
+}
+
+// IsTruthy returns truthiness of the value, and false as the second value when it is Unknown.
+func IsTruthy(v interface{}) (bool, bool) {
+	switch x := v.(type) {
+	case nil:
+		return false, true
+	case bool:
+		return x, true
+	case float64:
+		return x != 0 && !math.IsNaN(x), true
+	case string:
+		return x != "", true
+	case unknown:
+		return false, false
+	}
+	return true, true
+}
+
+// isEqual compares values loosely, like GitHub does: strings are case-insensitive, and values of different
+// types are converted to numbers.
+func isEqual(a interface{}, b interface{}) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return strings.EqualFold(as, bs)
+	}
+	if a == nil && b == nil {
+		return true
+	}
+	if ab, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			return ab == bb
+		}
+	}
+	return toNumber(a) == toNumber(b)
+}
+
+func toNumber(v interface{}) float64 {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if x {
+			return 1
+		}
+		return 0
+	case float64:
+		return x
+	case string:
+		s := strings.TrimSpace(x)
+		if s == "" {
+			return 0
+		}
+		n, err := parseNumber(s)
+		if err != nil {
+			return math.NaN()
+		}
+		return n
+	}
+	return math.NaN()
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		if x {
+			return "true"
+		}
+		return "false"
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case string:
+		return x
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// sexpr returns the parsed expression in a prefix notation, which is easy to compare.
+func sexpr(n Node) string {
+	switch v := n.(type) {
+	case *Literal:
+		if s, ok := v.Value.(string); ok {
+			return fmt.Sprintf("'%s'", s)
+		}
+		return fmt.Sprint(v.Value)
+	case *Context:
+		return v.Name
+	case *Property:
+		return fmt.Sprintf("(. %s %s)", sexpr(v.Object), v.Name)
+	case *Index:
+		return fmt.Sprintf("([] %s %s)", sexpr(v.Object), sexpr(v.Index))
+	case *Call:
+		args := []string{v.Name}
+		for _, a := range v.Args {
+			args = append(args, sexpr(a))
+		}
+		return "(" + strings.Join(args, " ") + ")"
+	case *Not:
+		return fmt.Sprintf("(! %s)", sexpr(v.Operand))
+	case *Binary:
+		return fmt.Sprintf("(%s %s %s)", v.Operator, sexpr(v.Left), sexpr(v.Right))
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+		err  bool
+	}{
+		{"true", "true", false},
+		{"null", "<nil>", false},
+		{"0x10", "16", false},
+		{"'it''s'", "'it's'", false},
+		{"github.event_name == 'push'", "(== (. github event_name) 'push')", false},
+		{"a || b && !c", "(|| a (&& b (! c)))", false},
+		{"(a || b) && c", "(&& (|| a b) c)", false},
+		{"github.event.pull_request.labels.*.name", "(. (. (. (. (. github event) pull_request) labels) *) name)", false},
+		{"matrix['os']", "([] matrix 'os')", false},
+		{"contains(github.ref, 'refs/tags/')", "(contains (. github ref) 'refs/tags/')", false},
+		{"StartsWith(a, 'x')", "(startswith a 'x')", false},
+		{"1 < 2 == true", "(== (< 1 2) true)", false},
+		{"a ==", "", true},
+		{"(a", "", true},
+		{"a b", "", true},
+		{"'unterminated", "", true},
+		{"a = b", "", true},
+	}
+	for _, tt := range tests {
+		n, err := Parse(tt.s)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Parse(%q) = %s, want an error", tt.s, sexpr(n))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %s", tt.s, err)
+			continue
+		}
+		if got := sexpr(n); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	values := Values{
+		"github.event_name": "push",
+		"github.ref":        "refs/tags/v1.0",
+		"inputs.count":      "3",
+	}
+	tests := []struct {
+		s    string
+		want interface{}
+	}{
+		{"github.event_name == 'PUSH'", true},
+		{"github.event_name != 'push'", false},
+		{"startsWith(github.ref, 'refs/tags/')", true},
+		{"contains(github.ref, 'V1')", true},
+		{"endsWith(github.ref, '.1')", false},
+		{"format('{0}-{1}', 'a', 1)", "a-1"},
+		{"format('{{{0}}}', 'x')", "{x}"},
+		{"inputs.count > 2", true},
+		{"inputs.count == 3", true},
+		{"'' || 'default'", "default"},
+		{"null && true", nil},
+		{"!github.event_name", false},
+		{"always()", true},
+		{"success()", Unknown},
+		{"github.actor == 'x'", Unknown},
+		{"github.actor == 'x' || true", true},
+		{"github.actor == 'x' && false", false},
+		{"contains(github.event.labels.*.name, 'x')", Unknown},
+		{"1 == '1'", true},
+		{"true == 1", true},
+		{"'abc' < 'ABD'", true},
+		{"'abc' < 1", false},
+	}
+	for _, tt := range tests {
+		n, err := Parse(tt.s)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %s", tt.s, err)
+		}
+		if got := Eval(n, values); got != tt.want {
+			t.Errorf("Eval(%q) = %#v, want %#v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		s          string
+		stringOnly bool
+		status     bool
+		err        bool
+	}{
+		{"github.ref == 'refs/heads/main'", false, false, false},
+		{"${{ github.ref == 'refs/heads/main' }}", false, false, false},
+		{"  ${{\n  always()\n}}\n", false, true, false},
+		{"${{ a }} && ${{ b }}", true, false, false},
+		{"${{ a }} == 'x'", true, false, false},
+		{"failure() || cancelled()", false, true, false},
+		{"${{ a == }}", false, false, true},
+	}
+	for _, tt := range tests {
+		c, err := ParseCondition(tt.s)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseCondition(%q) returned no error", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCondition(%q) returned error: %s", tt.s, err)
+			continue
+		}
+		if c.IsStringOnly != tt.stringOnly || c.HasStatusFunction() != tt.status {
+			t.Errorf("ParseCondition(%q) = %+v with status function %v, want string only %v and status function %v", tt.s, c, c.HasStatusFunction(), tt.stringOnly, tt.status)
+		}
+	}
+}
+
+func TestConditionEval(t *testing.T) {
+	values := Values{"github.event_name": "push"}
+	tests := []struct {
+		s       string
+		canFail bool
+		result  bool
+		known   bool
+	}{
+		{"github.event_name == 'push'", false, true, true},
+		{"github.event_name == 'push'", true, false, false},
+		{"github.event_name == 'pull_request'", true, false, true},
+		{"always() && github.event_name == 'push'", true, true, true},
+		{"success()", false, false, false},
+		{"${{ github.event_name }} == 'x'", true, true, true},
+		{"github.actor == 'x'", false, false, false},
+	}
+	for _, tt := range tests {
+		c, err := ParseCondition(tt.s)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q) returned error: %s", tt.s, err)
+		}
+		result, known := c.Eval(values, tt.canFail)
+		if result != tt.result || known != tt.known {
+			t.Errorf("Eval(%q, %v) = %v, %v, want %v, %v", tt.s, tt.canFail, result, known, tt.result, tt.known)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package expr parses and evaluates GitHub Actions expressions, eg. the ones in 'if' fields.  Values that
+// cannot be known without running the workflow evaluate to Unknown, so the package can tell when a condition
+// is statically decidable.
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenOperator
+	tokenNumber
+	tokenString
+	tokenIdent
+)
+
+type token struct {
+	kind  int
+	value string
+	num   float64
+	pos   int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!"}
+
+func tokenize(s string) ([]*token, error) {
+	var tokens []*token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.IndexByte("()[],.*", c) >= 0:
+			tokens = append(tokens, &token{kind: tokenPunct, value: string(c), pos: i})
+			i++
+		case c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for {
+				if j >= len(s) {
+					return nil, fmt.Errorf("unterminated string at position %d", i)
+				}
+				if s[j] == '\'' {
+					if j+1 < len(s) && s[j+1] == '\'' {
+						b.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				b.WriteByte(s[j])
+				j++
+			}
+			tokens = append(tokens, &token{kind: tokenString, value: b.String(), pos: i})
+			i = j + 1
+		case isDigit(c) || (c == '-' && i+1 < len(s) && isDigit(s[i+1])):
+			j := i + 1
+			for j < len(s) && (isDigit(s[j]) || isIdentChar(s[j]) || s[j] == '.') {
+				j++
+			}
+			n, err := parseNumber(s[i:j])
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s' at position %d", s[i:j], i)
+			}
+			tokens = append(tokens, &token{kind: tokenNumber, value: s[i:j], num: n, pos: i})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && isIdentChar(s[j]) {
+				j++
+			}
+			tokens = append(tokens, &token{kind: tokenIdent, value: s[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", c, i)
+			}
+			tokens = append(tokens, &token{kind: tokenOperator, value: op, pos: i})
+			i += len(op)
+		}
+	}
+	tokens = append(tokens, &token{kind: tokenEOF, pos: len(s)})
+	return tokens, nil
+}
+
+func parseNumber(s string) (float64, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "-0x") {
+		n, err := strconv.ParseInt(s, 0, 64)
+		return float64(n), err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '-'
+}
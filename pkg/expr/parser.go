@@ -0,0 +1,263 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a node of a parsed expression: *Literal, *Context, *Property, *Index, *Call, *Not or *Binary.
+type Node interface{}
+
+// Literal is null, a boolean, a number or a string.  Value is nil, bool, float64 or string.
+type Literal struct {
+	Value interface{}
+}
+
+// Context is a name of a context, eg. 'github'.
+type Context struct {
+	Name string
+}
+
+// Property is a dereference with '.name', or '.*' when Name is '*'.
+type Property struct {
+	Object Node
+	Name   string
+}
+
+// Index is a dereference with '[index]'.
+type Index struct {
+	Object Node
+	Index  Node
+}
+
+// Call is a function call.  Name is lowercase.
+type Call struct {
+	Name string
+	Args []Node
+}
+
+type Not struct {
+	Operand Node
+}
+
+// Binary is a logical or comparison operator.
+type Binary struct {
+	Operator string
+	Left     Node
+	Right    Node
+}
+
+type parser struct {
+	tokens []*token
+	pos    int
+}
+
+// Parse parses an expression without the surrounding '${{' and '}}'.
+func Parse(s string) (Node, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", t.value, t.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() *token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() *token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(kind int, value string) bool {
+	t := p.peek()
+	if t.kind == kind && t.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind int, value string) error {
+	if !p.accept(kind, value) {
+		t := p.peek()
+		if t.kind == tokenEOF {
+			return fmt.Errorf("expected '%s' at the end", value)
+		}
+		return fmt.Errorf("expected '%s' at position %d, found '%s'", value, t.pos, t.value)
+	}
+	return nil
+}
+
+func (p *parser) parseBinary(operators []string, operand func() (Node, error)) (Node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		matched := false
+		for _, o := range operators {
+			if t.kind == tokenOperator && t.value == o {
+				matched = true
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Operator: t.value, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseBinary([]string{"||"}, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseBinary([]string{"&&"}, p.parseEquality)
+}
+
+func (p *parser) parseEquality() (Node, error) {
+	return p.parseBinary([]string{"==", "!="}, p.parseComparison)
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	return p.parseBinary([]string{"<", "<=", ">", ">="}, p.parseUnary)
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.accept(tokenOperator, "!") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: n}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (Node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept(tokenPunct, "."):
+			t := p.next()
+			if t.kind == tokenIdent || (t.kind == tokenPunct && t.value == "*") {
+				n = &Property{Object: n, Name: t.value}
+				continue
+			}
+			return nil, fmt.Errorf("expected property name at position %d", t.pos)
+		case p.accept(tokenPunct, "["):
+			if p.accept(tokenPunct, "*") {
+				n = &Property{Object: n, Name: "*"}
+			} else {
+				i, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				n = &Index{Object: n, Index: i}
+			}
+			if err := p.expect(tokenPunct, "]"); err != nil {
+				return nil, err
+			}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return &Literal{Value: t.num}, nil
+	case tokenString:
+		return &Literal{Value: t.value}, nil
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return &Literal{Value: true}, nil
+		case "false":
+			return &Literal{Value: false}, nil
+		case "null":
+			return &Literal{Value: nil}, nil
+		case "NaN", "Infinity":
+			return nil, fmt.Errorf("unexpected '%s' at position %d", t.value, t.pos)
+		}
+		if p.accept(tokenPunct, "(") {
+			call := &Call{Name: strings.ToLower(t.value)}
+			if !p.accept(tokenPunct, ")") {
+				for {
+					a, err := p.parseOr()
+					if err != nil {
+						return nil, err
+					}
+					call.Args = append(call.Args, a)
+					if p.accept(tokenPunct, ")") {
+						break
+					}
+					if err := p.expect(tokenPunct, ","); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return call, nil
+		}
+		return &Context{Name: strings.ToLower(t.value)}, nil
+	case tokenPunct:
+		if t.value == "(" {
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenPunct, ")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected '%s' at position %d", t.value, t.pos)
+}
+
+// Walk calls fn for the node and all its descendants.
+func Walk(n Node, fn func(Node)) {
+	fn(n)
+	switch v := n.(type) {
+	case *Property:
+		Walk(v.Object, fn)
+	case *Index:
+		Walk(v.Object, fn)
+		Walk(v.Index, fn)
+	case *Call:
+		for _, a := range v.Args {
+			Walk(a, fn)
+		}
+	case *Not:
+		Walk(v.Operand, fn)
+	case *Binary:
+		Walk(v.Left, fn)
+		Walk(v.Right, fn)
+	}
+}
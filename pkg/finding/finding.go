@@ -0,0 +1,114 @@
+package finding
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	SortByFile     = "file"
+	SortByCode     = "code"
+	SortBySeverity = "severity"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota + 1
+	SeverityWarning
+	SeverityNaming
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityNaming:
+		return "naming"
+	}
+	return "unknown"
+}
+
+type Finding struct {
+	Code        string
+	Severity    Severity
+	File        string
+	Line        int
+	Name        string
+	Description string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %-120s %s", f.Code, f.Position()+" "+f.Name, f.Description)
+}
+
+// Position returns 'file:line' of the finding, or just the file when the finding is about the whole file.
+func (f *Finding) Position() string {
+	if f.Line <= 0 {
+		return f.File
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// SeverityOf returns severity derived from the first letter of the code, see README for the naming of codes.
+func SeverityOf(code string) Severity {
+	if len(code) > 0 {
+		switch code[0] {
+		case 'E':
+			return SeverityError
+		case 'N':
+			return SeverityNaming
+		}
+	}
+	return SeverityWarning
+}
+
+// Sort orders findings in place.  Whatever the primary key is, remaining fields are used to break ties so that
+// the order is always the same for the same set of findings.
+func Sort(findings []*Finding, by string) error {
+	var less func(a, b *Finding) bool
+	switch by {
+	case "", SortByFile:
+		less = func(a, b *Finding) bool {
+			return lessByFile(a, b)
+		}
+	case SortByCode:
+		less = func(a, b *Finding) bool {
+			if a.Code != b.Code {
+				return a.Code < b.Code
+			}
+			return lessByFile(a, b)
+		}
+	case SortBySeverity:
+		less = func(a, b *Finding) bool {
+			if a.Severity != b.Severity {
+				return a.Severity < b.Severity
+			}
+			return lessByFile(a, b)
+		}
+	default:
+		return fmt.Errorf("Invalid sort order '%s', should be one of: %s, %s, %s", by, SortByFile, SortByCode, SortBySeverity)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return less(findings[i], findings[j])
+	})
+	return nil
+}
+
+func lessByFile(a, b *Finding) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	if a.Code != b.Code {
+		return a.Code < b.Code
+	}
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.Description < b.Description
+}
@@ -0,0 +1,74 @@
+package finding
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	findings := func() []*Finding {
+		return []*Finding{
+			{Code: "WW101", Severity: SeverityWarning, File: "b.yml", Line: 3, Name: "x"},
+			{Code: "EW201", Severity: SeverityError, File: "b.yml", Line: 1, Name: "x"},
+			{Code: "NW106", Severity: SeverityNaming, File: "a.yml", Line: 9, Name: "x"},
+			{Code: "EW101", Severity: SeverityError, File: "b.yml", Line: 3, Name: "y"},
+			{Code: "EW101", Severity: SeverityError, File: "b.yml", Line: 3, Name: "x", Description: "b"},
+			{Code: "EW101", Severity: SeverityError, File: "b.yml", Line: 3, Name: "x", Description: "a"},
+		}
+	}
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{"", "a.yml:9:NW106 b.yml:1:EW201 b.yml:3:EW101:x:a b.yml:3:EW101:x:b b.yml:3:EW101:y: b.yml:3:WW101:x:"},
+		{SortByFile, "a.yml:9:NW106 b.yml:1:EW201 b.yml:3:EW101:x:a b.yml:3:EW101:x:b b.yml:3:EW101:y: b.yml:3:WW101:x:"},
+		{SortByCode, "b.yml:3:EW101:x:a b.yml:3:EW101:x:b b.yml:3:EW101:y: b.yml:1:EW201 a.yml:9:NW106 b.yml:3:WW101:x:"},
+		{SortBySeverity, "b.yml:1:EW201 b.yml:3:EW101:x:a b.yml:3:EW101:x:b b.yml:3:EW101:y: b.yml:3:WW101:x: a.yml:9:NW106"},
+	}
+	for _, tt := range tests {
+		f := findings()
+		err := Sort(f, tt.by)
+		if err != nil {
+			t.Fatalf("Sort(%q) returned error: %s", tt.by, err)
+		}
+		got := ""
+		for i, x := range f {
+			if i > 0 {
+				got += " "
+			}
+			got += fmt.Sprintf("%s:%d:%s", x.File, x.Line, x.Code)
+			if x.Line == 3 {
+				got += fmt.Sprintf(":%s:%s", x.Name, x.Description)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("Sort(%q):\ngot  %s\nwant %s", tt.by, got, tt.want)
+		}
+	}
+	if err := Sort(findings(), "name"); err == nil {
+		t.Error("Sort with invalid order returned no error")
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		f    *Finding
+		want string
+	}{
+		{
+			&Finding{Code: "EW101", File: ".github/workflows/main.yml", Line: 12, Name: "workflow main.yml", Description: "Invalid"},
+			"EW101: .github/workflows/main.yml:12 workflow main.yml",
+		},
+		{
+			&Finding{Code: "NW101", File: ".github/workflows/Main.yml", Name: "workflow Main.yml", Description: "Invalid"},
+			"NW101: .github/workflows/Main.yml workflow Main.yml",
+		},
+	}
+	for _, tt := range tests {
+		got := tt.f.String()
+		if !strings.HasPrefix(got, tt.want+" ") || !strings.HasSuffix(got, " Invalid") {
+			t.Errorf("String() = %q, want it to start with %q and end with the description", got, tt.want)
+		}
+	}
+}
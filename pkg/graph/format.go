@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Write writes the graph in the format, which is 'dot' when empty.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case "", FormatDOT:
+		return g.WriteDOT(w)
+	case FormatMermaid:
+		return g.WriteMermaid(w)
+	}
+	return fmt.Errorf("Invalid graph format '%s'", format)
+}
+
+var dotShapes = map[string]string{
+	NodeWorkflow:         "shape=folder, style=bold",
+	NodeJob:              "shape=box",
+	NodeStep:             "shape=box, style=rounded",
+	NodeAction:           "shape=component",
+	NodeExternalWorkflow: "shape=folder, style=dashed",
+}
+
+var dotEdges = map[string]string{
+	EdgeJob:   "",
+	EdgeNeeds: " [style=dashed, label=\"needs\"]",
+	EdgeStep:  "",
+	EdgeUses:  " [label=\"uses\"]",
+	EdgeCalls: " [label=\"calls\"]",
+}
+
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph github {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=\"%s\", %s];\n", n.ID, escapeDOT(n.Label), dotShapes[n.Kind])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", e.From, e.To, dotEdges[e.Kind])
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var mermaidShapes = map[string][2]string{
+	NodeWorkflow:         {"[[", "]]"},
+	NodeJob:              {"[", "]"},
+	NodeStep:             {"(", ")"},
+	NodeAction:           {"{{", "}}"},
+	NodeExternalWorkflow: {"[/", "/]"},
+}
+
+var mermaidEdges = map[string]string{
+	EdgeJob:   "-->",
+	EdgeNeeds: "-.->|needs|",
+	EdgeStep:  "-->",
+	EdgeUses:  "-->|uses|",
+	EdgeCalls: "-->|calls|",
+}
+
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		shape := mermaidShapes[n.Kind]
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", n.ID, shape[0], escapeMermaid(n.Label), shape[1])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s %s %s\n", e.From, mermaidEdges[e.Kind], e.To)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
@@ -0,0 +1,189 @@
+// Package graph builds a dependency graph of a .github directory: workflows, their jobs with 'needs', steps,
+// local actions with the actions they call, and reusable workflows.  It can be written in Graphviz DOT or
+// Mermaid format.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+)
+
+const (
+	NodeWorkflow         = "workflow"
+	NodeJob              = "job"
+	NodeStep             = "step"
+	NodeAction           = "action"
+	NodeExternalWorkflow = "external-workflow"
+)
+
+const (
+	EdgeJob   = "job"
+	EdgeNeeds = "needs"
+	EdgeStep  = "step"
+	EdgeUses  = "uses"
+	EdgeCalls = "calls"
+)
+
+// Node is a vertex of the graph.  ID is unique within the graph and safe to use in both formats.
+type Node struct {
+	ID    string
+	Kind  string
+	Label string
+}
+
+// Edge goes from the node that depends on, contains or calls the To node.
+type Edge struct {
+	From string
+	To   string
+	Kind string
+}
+
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+	ids   map[string]string
+}
+
+// Options configure what is included in the graph.  When WithoutSteps is set, jobs and actions are connected
+// to the actions they call directly.
+type Options struct {
+	WithoutSteps bool
+}
+
+// Build creates the graph from parsed actions and workflows.  Nodes and edges are always in the same order.
+func Build(d *dotgithub.DotGithub, opts Options) *Graph {
+	g := &Graph{ids: map[string]string{}}
+
+	for _, aName := range sortedKeys(d.Actions) {
+		g.addNode("a/"+aName, NodeAction, aName)
+	}
+	for _, wName := range sortedKeys(d.Workflows) {
+		label := wName
+		if d.Workflows[wName].Name != "" {
+			label = fmt.Sprintf("%s (%s)", d.Workflows[wName].Name, wName)
+		}
+		g.addNode("w/"+wName, NodeWorkflow, label)
+	}
+
+	for _, wName := range sortedKeys(d.Workflows) {
+		w := d.Workflows[wName]
+		for _, jName := range sortedKeys(w.Jobs) {
+			j := w.Jobs[jName]
+			if j == nil {
+				continue
+			}
+			jKey := "j/" + wName + "/" + jName
+			label := jName
+			if j.Name != "" && j.Name != jName {
+				label = fmt.Sprintf("%s (%s)", j.Name, jName)
+			}
+			g.addNode(jKey, NodeJob, label)
+			g.addEdge("w/"+wName, jKey, EdgeJob)
+		}
+		for _, jName := range sortedKeys(w.Jobs) {
+			j := w.Jobs[jName]
+			if j == nil {
+				continue
+			}
+			jKey := "j/" + wName + "/" + jName
+			for _, needed := range j.GetNeeds() {
+				if w.Jobs[needed] != nil {
+					g.addEdge(jKey, "j/"+wName+"/"+needed, EdgeNeeds)
+				}
+			}
+			if j.Uses != "" {
+				g.addEdge(jKey, g.workflowKey(d, j.Uses), EdgeCalls)
+			}
+			g.addSteps(d, jKey, j.Steps, opts)
+		}
+	}
+
+	for _, aName := range sortedKeys(d.Actions) {
+		a := d.Actions[aName]
+		if a.Runs != nil {
+			g.addSteps(d, "a/"+aName, a.Runs.Steps, opts)
+		}
+	}
+	return g
+}
+
+func (g *Graph) addSteps(d *dotgithub.DotGithub, parentKey string, steps []*action.ActionStep, opts Options) {
+	for i, s := range steps {
+		if s == nil {
+			continue
+		}
+		actionName := dotgithub.GetLocalActionName(s.Uses)
+		if d.Actions[actionName] == nil {
+			actionName = ""
+		}
+		// Nested actions are always connected directly to the composite action calling them.
+		if opts.WithoutSteps || strings.HasPrefix(parentKey, "a/") {
+			if actionName != "" {
+				g.addEdge(parentKey, "a/"+actionName, EdgeUses)
+			}
+			continue
+		}
+		sKey := fmt.Sprintf("s/%s/%d", strings.TrimPrefix(parentKey, "j/"), i)
+		g.addNode(sKey, NodeStep, stepLabel(i, s))
+		g.addEdge(parentKey, sKey, EdgeStep)
+		if actionName != "" {
+			g.addEdge(sKey, "a/"+actionName, EdgeUses)
+		}
+	}
+}
+
+// workflowKey returns key of the called workflow node, adding it when it is an external one.
+func (g *Graph) workflowKey(d *dotgithub.DotGithub, uses string) string {
+	if strings.HasPrefix(uses, "./.github/workflows/") {
+		name := strings.TrimPrefix(uses, "./.github/workflows/")
+		if d.Workflows[name] != nil {
+			return "w/" + name
+		}
+	}
+	key := "x/" + uses
+	if _, ok := g.ids[key]; !ok {
+		g.addNode(key, NodeExternalWorkflow, uses)
+	}
+	return key
+}
+
+func (g *Graph) addNode(key string, kind string, label string) {
+	id := fmt.Sprintf("n%d", len(g.Nodes)+1)
+	g.ids[key] = id
+	g.Nodes = append(g.Nodes, &Node{ID: id, Kind: kind, Label: label})
+}
+
+func (g *Graph) addEdge(fromKey string, toKey string, kind string) {
+	g.Edges = append(g.Edges, &Edge{From: g.ids[fromKey], To: g.ids[toKey], Kind: kind})
+}
+
+// stepLabel returns name, id, 'uses' or first line of 'run' of the step, whichever is set first.
+func stepLabel(i int, s *action.ActionStep) string {
+	label := s.Name
+	if label == "" {
+		label = s.Id
+	}
+	if label == "" {
+		label = s.Uses
+	}
+	if label == "" {
+		label = strings.TrimSpace(strings.SplitN(strings.TrimSpace(s.Run), "\n", 2)[0])
+		if len(label) > 40 {
+			label = label[:37] + "..."
+		}
+	}
+	return fmt.Sprintf("%d: %s", i, label)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
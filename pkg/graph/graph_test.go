@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+)
+
+// edgeLabels returns 'uses' edges of the graph as 'from -> to' strings with labels of the nodes.
+func edgeLabels(g *Graph) []string {
+	labels := map[string]string{}
+	for _, n := range g.Nodes {
+		labels[n.ID] = n.Label
+	}
+	edges := []string{}
+	for _, e := range g.Edges {
+		if e.Kind == EdgeUses {
+			edges = append(edges, fmt.Sprintf("%s -> %s", labels[e.From], labels[e.To]))
+		}
+	}
+	return edges
+}
+
+func TestBuildNestedActions(t *testing.T) {
+	d := &dotgithub.DotGithub{
+		Path: filepath.Join("testdata", "nested", ".github"),
+	}
+	err := d.InitFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		opts Options
+		want []string
+	}{
+		{Options{}, []string{"1: ./.github/actions/parent/child -> parent/child", "2: ./.github/actions/parent/ -> parent", "parent -> parent/child"}},
+		{Options{WithoutSteps: true}, []string{"main -> parent/child", "main -> parent", "parent -> parent/child"}},
+	}
+	for _, tt := range tests {
+		got := edgeLabels(Build(d, tt.opts))
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Build(%+v): got %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
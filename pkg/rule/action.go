@@ -0,0 +1,158 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EA001", "Cannot parse YAML of action file", nil)
+	registerBuiltin("EA002", "Cannot read action file", nil)
+	registerBuiltin("NA101", "Action directory name should follow the naming convention, kebab by default", validateActionDirName, NodeAction)
+	registerBuiltin("NA102", "Action file name should have .yml extension", validateActionFileName, NodeAction)
+	registerBuiltin("NA103", "Action name is empty", validateActionName, NodeAction)
+	registerBuiltin("NA104", "Action description is empty", validateActionDescription, NodeAction)
+	registerBuiltin("NA105", "Called variable name should follow the naming convention, SCREAMING_SNAKE by default", validateActionCalledVarNames, NodeAction)
+	registerBuiltin("EA201", "Called variable is invalid", validateCalledVarsInvalid, NodeAction)
+	registerBuiltin("EA202", "Called input does not exist", validateActionCalledInputs, NodeAction)
+	registerBuiltin("EA203", "Called step does not exist because action has no 'runs'", validateActionCalledStepsWithoutRuns, NodeAction)
+	registerBuiltin("EA204", "Called step does not exist", validateActionCalledSteps, NodeAction)
+	registerBuiltin("WW201", "Called variable may not need to be in double quotes", validateCalledVarsNotInDoubleQuotes, NodeAction, NodeWorkflow)
+}
+
+// validateActionDirName checks each part of the directory name as actions can be nested in a sub-directory.
+func validateActionDirName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, dirName := range strings.Split(n.Action.DirName, "/") {
+		m, desc, err := matchNaming(n, "NA101", config.NamingKebab, dirName)
+		if err != nil {
+			return validationErrors, err
+		}
+		if !m {
+			validationErrors = append(validationErrors, n.Finding("Action directory name should "+desc))
+			break
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateActionFileName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	m, err := regexp.MatchString(`\.yml$`, n.Action.Path)
+	if err != nil {
+		return validationErrors, err
+	}
+	if !m {
+		validationErrors = append(validationErrors, n.Finding("Action file name should have .yml extension"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Name == "" {
+		validationErrors = append(validationErrors, n.Finding("Action name is empty"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionDescription(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Description == "" {
+		validationErrors = append(validationErrors, n.Finding("Action description is empty"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionCalledVarNames(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	varTypes := []string{"env", "var", "secret"}
+	for _, v := range varTypes {
+		re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*%s\\.([a-zA-Z0-9\\-_]+)[ ]*}}", v))
+		found := re.FindAllSubmatchIndex(n.Raw(), -1)
+		for _, f := range found {
+			called := string(n.Raw()[f[2]:f[3]])
+			m, desc, err := matchNaming(n, "NA105", config.NamingScreamingSnake, called)
+			if err != nil {
+				return validationErrors, err
+			}
+			if !m {
+				validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called variable name '%s' should %s", called, desc)))
+			}
+		}
+	}
+	return validationErrors, nil
+}
+
+// validateCalledVarsInvalid is shared between actions (EA201) and workflows (EW201).
+func validateCalledVarsInvalid(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*([a-zA-Z0-9\\-_]+)[ ]*}}"))
+	found := re.FindAllSubmatchIndex(n.Raw(), -1)
+	for _, f := range found {
+		called := string(n.Raw()[f[2]:f[3]])
+		if called != "false" && called != "true" {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called variable '%s' is invalid", called)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateActionCalledInputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*inputs\\.([a-zA-Z0-9\\-_]+)[ ]*}}"))
+	found := re.FindAllSubmatchIndex(n.Raw(), -1)
+	for _, f := range found {
+		called := string(n.Raw()[f[2]:f[3]])
+		if n.Action.Inputs == nil || n.Action.Inputs[called] == nil {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called input '%s' does not exist", called)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateActionCalledStepsWithoutRuns(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs != nil {
+		return validationErrors, nil
+	}
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*steps\\.([a-zA-Z0-9\\-_]+)\\.outputs\\.[a-zA-Z0-9\\-_]+[ ]*}}"))
+	found := re.FindAllSubmatchIndex(n.Raw(), -1)
+	for _, f := range found {
+		called := string(n.Raw()[f[2]:f[3]])
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called step with id '%s' does not exist", called)))
+	}
+	return validationErrors, nil
+}
+
+func validateActionCalledSteps(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs == nil {
+		return validationErrors, nil
+	}
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*steps\\.([a-zA-Z0-9\\-_]+)\\.outputs\\.[a-zA-Z0-9\\-_]+[ ]*}}"))
+	found := re.FindAllSubmatchIndex(n.Raw(), -1)
+	for _, f := range found {
+		called := string(n.Raw()[f[2]:f[3]])
+		if !n.Action.Runs.IsStepExist(called) {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called step with id '%s' does not exist", called)))
+		}
+	}
+	return validationErrors, nil
+}
+
+// validateCalledVarsNotInDoubleQuotes is shared between actions and workflows.
+func validateCalledVarsNotInDoubleQuotes(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	re := regexp.MustCompile(`\"\${{[ ]*([a-zA-Z0-9\\-_.]+)[ ]*}}\"`)
+	found := re.FindAllSubmatchIndex(n.Raw(), -1)
+	for _, f := range found {
+		called := string(n.Raw()[f[2]:f[3]])
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called variable '%s' may not need to be in double quotes", called)))
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,158 @@
+package rule
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/expr"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EA881", "Condition in 'if' is not a valid expression", validateConditionSyntax, NodeActionStep)
+	registerBuiltin("EW881", "Condition in 'if' is not a valid expression", validateConditionSyntax, NodeWorkflowJob, NodeWorkflowStep)
+	registerBuiltin("EA882", "Condition in 'if' has text outside '${{ }}' and is always true", validateConditionMixedText, NodeActionStep)
+	registerBuiltin("EW882", "Condition in 'if' has text outside '${{ }}' and is always true", validateConditionMixedText, NodeWorkflowJob, NodeWorkflowStep)
+	registerBuiltin("WA883", "Condition in 'if' is always true or always false", validateConditionConstant, NodeActionStep)
+	registerBuiltin("WW883", "Condition in 'if' is always true or always false", validateConditionConstant, NodeWorkflowJob, NodeWorkflowStep)
+	registerBuiltin("EA884", "Status function can be used only in 'if'", validateStatusFunctions, NodeAction)
+	registerBuiltin("EW884", "Status function can be used only in 'if'", validateStatusFunctions, NodeWorkflow)
+}
+
+// getCondition returns 'if' of the job or the step, with its line.
+func getCondition(n *Node) (string, int) {
+	if n.Kind == NodeWorkflowJob {
+		return n.Job.If, n.Job.IfLine
+	}
+	return n.Step.If, n.Step.IfLine
+}
+
+// conditionText returns the condition in a single line, to be used in a message.
+func conditionText(cond string) string {
+	return strings.Join(strings.Fields(cond), " ")
+}
+
+func validateConditionSyntax(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	cond, line := getCondition(n)
+	if cond == "" {
+		return validationErrors, nil
+	}
+	_, err := expr.ParseCondition(cond)
+	if err != nil {
+		validationErrors = append(validationErrors, n.FindingAtLine(line, fmt.Sprintf("Condition '%s' in 'if' is not a valid expression: %s", conditionText(cond), err.Error())))
+	}
+	return validationErrors, nil
+}
+
+func validateConditionMixedText(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	cond, line := getCondition(n)
+	if cond == "" {
+		return validationErrors, nil
+	}
+	c, err := expr.ParseCondition(cond)
+	if err == nil && c.IsStringOnly {
+		validationErrors = append(validationErrors, n.FindingAtLine(line, fmt.Sprintf("Condition '%s' in 'if' has text outside '${{ }}', so it is a string and is always true - put the whole condition inside '${{ }}' or remove it", conditionText(cond))))
+	}
+	return validationErrors, nil
+}
+
+// unknownStatus makes status functions, including always(), unknown, as they are used deliberately.
+var unknownStatus = expr.Values{
+	"success()":   expr.Unknown,
+	"failure()":   expr.Unknown,
+	"cancelled()": expr.Unknown,
+	"always()":    expr.Unknown,
+}
+
+func validateConditionConstant(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	cond, line := getCondition(n)
+	if cond == "" {
+		return validationErrors, nil
+	}
+	c, err := expr.ParseCondition(cond)
+	if err != nil || c.IsStringOnly {
+		return validationErrors, nil
+	}
+	v := expr.Eval(c.Node, unknownStatus)
+	t, known := expr.IsTruthy(v)
+	if !known {
+		return validationErrors, nil
+	}
+	if s, ok := v.(string); ok && t {
+		validationErrors = append(validationErrors, n.FindingAtLine(line, fmt.Sprintf("Condition '%s' in 'if' is string '%s', which is always true", conditionText(cond), s)))
+		return validationErrors, nil
+	}
+	validationErrors = append(validationErrors, n.FindingAtLine(line, fmt.Sprintf("Condition '%s' in 'if' is always %t", conditionText(cond), t)))
+	return validationErrors, nil
+}
+
+// exprLine returns line in the file of the expression starting at idx in value of the YAML scalar.  The value
+// can be folded, so the expression is found in the file by counting the expressions before it.
+func exprLine(n *Node, v *yaml.Node, idx int) int {
+	raw := n.Raw()
+	offset := 0
+	for line := 1; line < v.Line && offset < len(raw); line++ {
+		next := bytes.IndexByte(raw[offset:], '\n')
+		if next < 0 {
+			return v.Line
+		}
+		offset += next + 1
+	}
+	for k := strings.Count(v.Value[:idx], "${{"); k >= 0; k-- {
+		next := bytes.Index(raw[offset:], []byte("${{"))
+		if next < 0 {
+			return v.Line
+		}
+		offset += next
+		if k > 0 {
+			offset += 3
+		}
+	}
+	return n.LineAt(offset)
+}
+
+// validateStatusFunctions checks all the expressions in the file, as status functions are not allowed in any
+// field other than 'if' of a job or a step.
+func validateStatusFunctions(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	var root yaml.Node
+	if yaml.Unmarshal(n.Raw(), &root) != nil {
+		return validationErrors, nil
+	}
+	re := regexp.MustCompile(`(?s)\$\{\{(.*?)\}\}`)
+	var walk func(v *yaml.Node, isIf bool)
+	walk = func(v *yaml.Node, isIf bool) {
+		switch v.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, c := range v.Content {
+				walk(c, false)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(v.Content); i += 2 {
+				walk(v.Content[i+1], v.Content[i].Value == "if")
+			}
+		case yaml.ScalarNode:
+			if isIf {
+				return
+			}
+			for _, f := range re.FindAllStringSubmatchIndex(v.Value, -1) {
+				parsed, err := expr.Parse(v.Value[f[2]:f[3]])
+				if err != nil {
+					continue
+				}
+				for _, name := range expr.StatusFunctions(parsed) {
+					validationErrors = append(validationErrors, n.FindingAtLine(exprLine(n, v, f[0]), fmt.Sprintf("Status function '%s()' can be used only in 'if' of a job or a step", name)))
+				}
+			}
+		}
+	}
+	walk(&root, false)
+	return validationErrors, nil
+}
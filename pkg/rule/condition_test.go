@@ -0,0 +1,14 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestConditionRules(t *testing.T) {
+	testFixture(t, "condition", nil, []fixtureCase{
+		{"EW881", []string{"EW881:26"}},
+		{"EW882", []string{"EW882:28"}},
+		{"WW883", []string{"WW883:30", "WW883:32", "WW883:34"}},
+		{"EW884", []string{"EW884:4", "EW884:14", "EW884:19", "EW884:23"}},
+	})
+}
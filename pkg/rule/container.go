@@ -0,0 +1,146 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
+)
+
+func init() {
+	registerBuiltin("EW621", "Image of job container or service is invalid", validateJobContainerImages, NodeWorkflowJob)
+	registerBuiltin("WW622", "Image of job container or service should not use 'latest' tag", validateJobContainerImagesLatest, NodeWorkflowJob)
+	registerBuiltin("EW623", "Port of job container or service is invalid", validateJobContainerPorts, NodeWorkflowJob)
+	registerBuiltin("EW624", "Job container and services can be used only on Linux runners", validateJobContainerRunner, NodeWorkflowJob)
+	registerBuiltin("EW625", "Password in credentials of job container or service should be a secret", validateJobContainerCredentials, NodeWorkflowJob)
+}
+
+// jobContainer is the container or a service of a job, with its name for messages.
+type jobContainer struct {
+	Name      string
+	Container *workflow.WorkflowContainer
+}
+
+// getJobContainers returns the container of the job, and its services sorted by name.
+func getJobContainers(n *Node) []jobContainer {
+	var containers []jobContainer
+	if n.Job.Container != nil {
+		containers = append(containers, jobContainer{Name: "container", Container: n.Job.Container})
+	}
+	for _, name := range sortedKeys(n.Job.Services) {
+		if n.Job.Services[name] != nil {
+			containers = append(containers, jobContainer{Name: "service '" + name + "'", Container: n.Job.Services[name]})
+		}
+	}
+	return containers
+}
+
+// imageReference matches '[registry[:port]/]name[:tag][@digest]' like docker does.
+var imageReference = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9.-]*[a-zA-Z0-9])?(?::[0-9]+)?/)?[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*(?::[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+
+func validateJobContainerImages(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, c := range getJobContainers(n) {
+		image := strings.TrimPrefix(c.Container.Image, "docker://")
+		if strings.Contains(image, "${{") {
+			continue
+		}
+		if image == "" {
+			// Container with empty image runs the job directly on the runner, but a service needs an image.
+			if c.Name != "container" {
+				validationErrors = append(validationErrors, n.FindingAtLine(c.Container.Line, fmt.Sprintf("Image of %s is missing", c.Name)))
+			}
+			continue
+		}
+		if !imageReference.MatchString(image) {
+			validationErrors = append(validationErrors, n.FindingAtLine(c.Container.Line, fmt.Sprintf("Image '%s' of %s is invalid", c.Container.Image, c.Name)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateJobContainerImagesLatest(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, c := range getJobContainers(n) {
+		image := c.Container.Image
+		if image == "" || strings.Contains(image, "${{") || strings.Contains(image, "@sha256:") {
+			continue
+		}
+		_, tag := splitImage(image)
+		if tag == "latest" {
+			validationErrors = append(validationErrors, n.FindingAtLine(c.Container.Line, fmt.Sprintf("Image '%s' of %s should not use 'latest' tag", image, c.Name)))
+		} else if tag == "" {
+			validationErrors = append(validationErrors, n.FindingAtLine(c.Container.Line, fmt.Sprintf("Image '%s' of %s does not have a tag, so 'latest' is used", image, c.Name)))
+		}
+	}
+	return validationErrors, nil
+}
+
+// containerPort matches '[ip:][host:]container[/protocol]', where ports can be ranges.
+var containerPort = regexp.MustCompile(`^(?:(?:[0-9]{1,3}\.){3}[0-9]{1,3}:)?(?:([0-9]+(?:-[0-9]+)?):)?([0-9]+(?:-[0-9]+)?)(?:/(?:tcp|udp|sctp))?$`)
+
+func isPortValid(port string) bool {
+	m := containerPort.FindStringSubmatch(port)
+	if m == nil {
+		return false
+	}
+	for _, r := range m[1:] {
+		if r == "" {
+			continue
+		}
+		for _, p := range strings.Split(r, "-") {
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 1 || i > 65535 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func validateJobContainerPorts(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, c := range getJobContainers(n) {
+		for _, port := range c.Container.Ports {
+			if strings.Contains(port, "${{") || isPortValid(port) {
+				continue
+			}
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOfFrom(c.Container.Line, port), fmt.Sprintf("Port '%s' of %s is invalid - use 'container', 'host:container' or 'host:container/protocol'", port, c.Name)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateJobContainerRunner(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	runnerOS := n.Job.RunsOn.GetOS()
+	if runnerOS == "" || runnerOS == "linux" {
+		return validationErrors, nil
+	}
+	if n.Job.Container != nil {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.Job.Container.Line, fmt.Sprintf("Field 'container' can be used only on Linux runners and not on %s", runnerOS)))
+	}
+	if len(n.Job.Services) > 0 {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf("services:"), fmt.Sprintf("Field 'services' can be used only on Linux runners and not on %s", runnerOS)))
+	}
+	return validationErrors, nil
+}
+
+var secretExpression = regexp.MustCompile(`^\$\{\{\s*(secrets\.[a-zA-Z0-9_\-]+|github\.token)\s*\}\}$`)
+
+func validateJobContainerCredentials(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, c := range getJobContainers(n) {
+		cred := c.Container.Credentials
+		if cred == nil || cred.Password == "" {
+			continue
+		}
+		if !secretExpression.MatchString(strings.TrimSpace(cred.Password)) {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOfFrom(cred.Line, "password:"), fmt.Sprintf("Password in credentials of %s should be a secret like '${{ secrets.NAME }}'", c.Name)))
+		}
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,68 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestIsPortValid(t *testing.T) {
+	tests := []struct {
+		port string
+		want bool
+	}{
+		{"80", true},
+		{"8080:80", true},
+		{"8080:80/tcp", true},
+		{"53:53/udp", true},
+		{"127.0.0.1:8080:80", true},
+		{"8000-8010:8000-8010", true},
+		{"0", false},
+		{"70000", false},
+		{"8080:", false},
+		{"80/http", false},
+		{"localhost:8080:80", false},
+		{"port", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isPortValid(tt.port); got != tt.want {
+			t.Errorf("isPortValid(%q) = %v, want %v", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{"alpine", true},
+		{"node:20-alpine", true},
+		{"library/node:20", true},
+		{"ghcr.io/org/image:1.0", true},
+		{"localhost:5000/image", true},
+		{"registry.example.com:443/a/b/c:v1.2.3", true},
+		{"postgres@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", true},
+		{"my_image__name-x:tag", true},
+		{"Redis", false},
+		{"image:", false},
+		{"image:-tag", false},
+		{"org//image", false},
+		{"image@sha256:123", false},
+		{"-image", false},
+	}
+	for _, tt := range tests {
+		if got := imageReference.MatchString(tt.image); got != tt.want {
+			t.Errorf("imageReference.MatchString(%q) = %v, want %v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestContainerRules(t *testing.T) {
+	testFixture(t, "container", nil, []fixtureCase{
+		{"EW621", []string{"EW621:20", "EW621:22"}},
+		{"WW622", []string{"WW622:7", "WW622:20", "WW622:27"}},
+		{"EW623", []string{"EW623:10"}},
+		{"EW624", []string{"EW624:33"}},
+		{"EW625", []string{"EW625:13"}},
+	})
+}
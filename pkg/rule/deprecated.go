@@ -0,0 +1,98 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("WA841", "Deprecated workflow command is used in run script", validateStepDeprecatedCommands, NodeActionStep)
+	registerBuiltin("WW841", "Deprecated workflow command is used in run script", validateStepDeprecatedCommands, NodeWorkflowStep)
+	registerBuiltin("EA842", "Disabled workflow command is used in run script", validateStepDisabledCommands, NodeActionStep)
+	registerBuiltin("EW842", "Disabled workflow command is used in run script", validateStepDisabledCommands, NodeWorkflowStep)
+	registerBuiltin("EA843", "Unsecure workflow commands are allowed with ACTIONS_ALLOW_UNSECURE_COMMANDS", validateUnsecureCommandsAllowed, NodeActionStep)
+	registerBuiltin("EW843", "Unsecure workflow commands are allowed with ACTIONS_ALLOW_UNSECURE_COMMANDS", validateUnsecureCommandsAllowed, NodeWorkflow, NodeWorkflowJob, NodeWorkflowStep)
+	registerBuiltin("WA812", "Called step output is set with deprecated '::set-output' command", validateActionStepCalledDeprecatedOutputs, NodeActionStep)
+	registerBuiltin("WW812", "Called step output is set with deprecated '::set-output' command", validateWorkflowStepCalledDeprecatedOutputs, NodeWorkflowStep)
+}
+
+// deprecatedCommands are workflow commands that still work but are replaced with environment files.
+var deprecatedCommands = map[string]string{
+	"set-output": "echo \"name=value\" >> $GITHUB_OUTPUT",
+	"save-state": "echo \"name=value\" >> $GITHUB_STATE",
+}
+
+// disabledCommands are workflow commands that do not work unless ACTIONS_ALLOW_UNSECURE_COMMANDS is set.
+var disabledCommands = map[string]string{
+	"set-env":  "echo \"NAME=value\" >> $GITHUB_ENV",
+	"add-path": "echo \"/path\" >> $GITHUB_PATH",
+}
+
+// workflowCommand matches '::command::' or '::command parameters::' and captures the command.
+var workflowCommand = regexp.MustCompile(`::([a-z\-]+)( [^:]*)?::`)
+
+func findWorkflowCommands(n *Node, commands map[string]string, desc string) []*finding.Finding {
+	var validationErrors []*finding.Finding
+	if n.Step.Run == "" {
+		return validationErrors
+	}
+	for _, line := range strings.Split(n.Step.Run, "\n") {
+		for _, m := range workflowCommand.FindAllStringSubmatch(line, -1) {
+			if replacement, ok := commands[m[1]]; ok {
+				validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(strings.TrimSpace(line)), fmt.Sprintf("%s command '::%s' is used - replace it with '%s'", desc, m[1], replacement)))
+			}
+		}
+	}
+	return validationErrors
+}
+
+func validateStepDeprecatedCommands(n *Node) ([]*finding.Finding, error) {
+	return findWorkflowCommands(n, deprecatedCommands, "Deprecated"), nil
+}
+
+func validateStepDisabledCommands(n *Node) ([]*finding.Finding, error) {
+	return findWorkflowCommands(n, disabledCommands, "Disabled"), nil
+}
+
+func validateUnsecureCommandsAllowed(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	var env map[string]string
+	switch n.Kind {
+	case NodeWorkflow:
+		env = n.Workflow.Env
+	case NodeWorkflowJob:
+		env = n.Job.Env
+	default:
+		env = n.Step.Env
+		if strings.Contains(n.Step.Run, "ACTIONS_ALLOW_UNSECURE_COMMANDS") {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf("ACTIONS_ALLOW_UNSECURE_COMMANDS"), "ACTIONS_ALLOW_UNSECURE_COMMANDS is used in run script - use environment files like $GITHUB_ENV and $GITHUB_PATH instead"))
+		}
+	}
+	if _, ok := env["ACTIONS_ALLOW_UNSECURE_COMMANDS"]; ok {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf("ACTIONS_ALLOW_UNSECURE_COMMANDS"), "ACTIONS_ALLOW_UNSECURE_COMMANDS is set in 'env' - use environment files like $GITHUB_ENV and $GITHUB_PATH instead"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionStepCalledDeprecatedOutputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, called := range findCalledStepOutputs(n.Step) {
+		if n.Action.Runs != nil && n.Action.Runs.IsStepOutputExist(called[0], called[1], n.DotGithub) == 1 {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called step with id '%s' output '%s' is set with deprecated '::set-output' command", called[0], called[1])))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowStepCalledDeprecatedOutputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, called := range findCalledStepOutputs(n.Step) {
+		if n.Job.IsStepOutputExist(called[0], called[1], n.DotGithub) == 1 {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called step with id '%s' output '%s' is set with deprecated '::set-output' command", called[0], called[1])))
+		}
+	}
+	return validationErrors, nil
+}
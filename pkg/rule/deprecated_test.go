@@ -0,0 +1,18 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestDeprecatedRules(t *testing.T) {
+	testFixture(t, "deprecated", nil, []fixtureCase{
+		{"WA841", []string{"WA841:12"}},
+		{"WW841", []string{"WW841:13", "WW841:14"}},
+		{"EA842", []string{"EA842:16"}},
+		{"EW842", []string{"EW842:15", "EW842:16", "EW842:21"}},
+		{"EA843", []string{}},
+		{"EW843", []string{"EW843:4", "EW843:9", "EW843:23", "EW843:24"}},
+		{"WA812", []string{"WA812:13"}},
+		{"WW812", []string{"WW812:20"}},
+	})
+}
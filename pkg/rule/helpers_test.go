@@ -0,0 +1,72 @@
+package rule
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+// runFixture runs rules with the codes on the .github directory from testdata/<name> and returns findings
+// sorted by file, line and code.
+func runFixture(t *testing.T, name string, cfg *config.Config, codes ...string) []*finding.Finding {
+	t.Helper()
+	d := &dotgithub.DotGithub{
+		Path: filepath.Join("testdata", name, ".github"),
+	}
+	err := d.InitFiles()
+	if err != nil {
+		t.Fatalf("cannot init %s: %s", name, err)
+	}
+	if len(d.InitErrors()) > 0 {
+		t.Fatalf("cannot parse %s: %s", name, d.InitErrors()[0])
+	}
+	findings, err := Builtin().Run(d, cfg, codes)
+	if err != nil {
+		t.Fatalf("cannot run rules on %s: %s", name, err)
+	}
+	err = finding.Sort(findings, finding.SortByFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return findings
+}
+
+// codeLines returns findings as 'CODE:line' strings, which are easy to compare with the expected ones.
+func codeLines(findings []*finding.Finding) []string {
+	s := []string{}
+	for _, f := range findings {
+		s = append(s, fmt.Sprintf("%s:%d", f.Code, f.Line))
+	}
+	return s
+}
+
+func assertCodeLines(t *testing.T, findings []*finding.Finding, want []string) {
+	t.Helper()
+	got := codeLines(findings)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		for _, f := range findings {
+			t.Logf("%d %s", f.Line, f)
+		}
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// fixtureCase is a rule code with its expected findings as 'CODE:line' strings.
+type fixtureCase struct {
+	code string
+	want []string
+}
+
+// testFixture runs each of the rules on testdata/<name> in a subtest and compares the lines of its findings.
+func testFixture(t *testing.T, name string, cfg *config.Config, cases []fixtureCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.code, func(t *testing.T) {
+			assertCodeLines(t, runFixture(t, name, cfg, c.code), c.want)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package rule
+
+import (
+	"strings"
+)
+
+// splitImage splits a docker image reference, eg. 'docker://ghcr.io/owner/name:tag@sha256:...', into the name
+// with the registry and the tag, which is empty when there is none.  The 'docker://' prefix and the digest are
+// dropped.  Port of the registry is not a tag.
+func splitImage(image string) (string, string) {
+	image = strings.TrimPrefix(image, "docker://")
+	image = strings.SplitN(image, "@", 2)[0]
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
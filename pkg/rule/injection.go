@@ -0,0 +1,75 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EA821", "Untrusted input is used directly in run script", validateStepRunInjection, NodeActionStep)
+	registerBuiltin("EW821", "Untrusted input is used directly in run script", validateStepRunInjection, NodeWorkflowStep)
+}
+
+// untrustedContexts are the contexts that can be set by anyone who opens an issue or a pull request, eg. a
+// title, a branch name or a commit message.  Indexes by a string, eg. ['title'], are replaced with a property
+// access and any other index with '*' before matching.
+var untrustedContexts = []*regexp.Regexp{
+	regexp.MustCompile(`^github\.event\.(issue|pull_request|discussion)\.(title|body)$`),
+	regexp.MustCompile(`^github\.event\.(comment|review|review_comment)\.body$`),
+	regexp.MustCompile(`^github\.event\.pages\.\*\.page_name$`),
+	regexp.MustCompile(`^github\.event\.commits\.\*\.(message|author\.email|author\.name)$`),
+	regexp.MustCompile(`^github\.event\.head_commit\.(message|author\.email|author\.name)$`),
+	regexp.MustCompile(`^github\.event\.pull_request\.head\.(ref|label|repo\.default_branch)$`),
+	regexp.MustCompile(`^github\.head_ref$`),
+	regexp.MustCompile(`^github\.event\.workflow_run\.(head_branch|display_title|head_commit\.(message|author\.email|author\.name))$`),
+	regexp.MustCompile(`^github\.event\.workflow_run\.pull_requests\.\*\.head\.ref$`),
+}
+
+var (
+	injectionExpr    = regexp.MustCompile(`\${{(.*?)}}`)
+	injectionContext = regexp.MustCompile(`[a-zA-Z_][a-zA-Z0-9_\-]*(\.[a-zA-Z0-9_\-\*]+|\[[^\]]*\])*`)
+	injectionIndex   = regexp.MustCompile(`\[\s*('([^']*)'|[^\]]*)\s*\]`)
+)
+
+// normalizeContext replaces indexes in the context with property accesses, eg. github['head_ref'] becomes
+// github.head_ref, and github.event.commits[0].message becomes github.event.commits.*.message.
+func normalizeContext(context string) string {
+	return injectionIndex.ReplaceAllStringFunc(context, func(index string) string {
+		m := injectionIndex.FindStringSubmatch(index)
+		if m[2] != "" {
+			return "." + m[2]
+		}
+		return ".*"
+	})
+}
+
+// findUntrustedContexts returns the untrusted contexts referenced in '${{ }}' expressions in s, along with the
+// whole expression they are in.
+func findUntrustedContexts(s string) [][2]string {
+	var found [][2]string
+	for _, expr := range injectionExpr.FindAllStringSubmatch(s, -1) {
+		for _, context := range injectionContext.FindAllString(expr[1], -1) {
+			normalized := normalizeContext(context)
+			for _, re := range untrustedContexts {
+				if re.MatchString(normalized) {
+					found = append(found, [2]string{context, expr[0]})
+					break
+				}
+			}
+		}
+	}
+	return found
+}
+
+func validateStepRunInjection(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Step.Run == "" {
+		return validationErrors, nil
+	}
+	for _, f := range findUntrustedContexts(n.Step.Run) {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(f[1]), fmt.Sprintf("Untrusted '%s' is used directly in run script - pass it to the step 'env' and use the env variable instead", f[0])))
+	}
+	return validationErrors, nil
+}
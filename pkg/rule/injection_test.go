@@ -0,0 +1,30 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestNormalizeContext(t *testing.T) {
+	tests := []struct {
+		context string
+		want    string
+	}{
+		{"github.event.pull_request.title", "github.event.pull_request.title"},
+		{"github.event['pull_request']['title']", "github.event.pull_request.title"},
+		{"github['head_ref']", "github.head_ref"},
+		{"github.event.commits[0].message", "github.event.commits.*.message"},
+		{"github.event.commits[*].message", "github.event.commits.*.message"},
+	}
+	for _, tt := range tests {
+		if got := normalizeContext(tt.context); got != tt.want {
+			t.Errorf("normalizeContext(%q) = %q, want %q", tt.context, got, tt.want)
+		}
+	}
+}
+
+func TestInjectionRules(t *testing.T) {
+	testFixture(t, "injection", nil, []fixtureCase{
+		{"EA821", []string{"EA821:7"}},
+		{"EW821", []string{"EW821:9", "EW821:10", "EW821:11", "EW821:12", "EW821:19"}},
+	})
+}
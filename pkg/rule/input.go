@@ -0,0 +1,54 @@
+package rule
+
+import (
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("NA301", "Action input name should follow the naming convention, kebab by default", validateInputName("NA301", "Action input name"), NodeActionInput)
+	registerBuiltin("NA302", "Action input must have a description", validateActionInputDescription, NodeActionInput)
+	registerBuiltin("NA501", "Action output name should follow the naming convention, kebab by default", validateInputName("NA501", "Action output name"), NodeActionOutput)
+	registerBuiltin("NA502", "Action output must have a description", validateActionOutputDescription, NodeActionOutput)
+	registerBuiltin("NW301", "Workflow input name should follow the naming convention, kebab by default", validateInputName("NW301", "Workflow input name"), NodeWorkflowInput)
+	registerBuiltin("NW302", "Workflow input must have a description", validateWorkflowInputDescription, NodeWorkflowInput)
+}
+
+// validateInputName checks names of inputs and outputs, which both are in the Name field of the node.
+func validateInputName(code string, subject string) func(n *Node) ([]*finding.Finding, error) {
+	return func(n *Node) ([]*finding.Finding, error) {
+		var validationErrors []*finding.Finding
+		m, desc, err := matchNaming(n, code, config.NamingKebab, n.Name)
+		if err != nil {
+			return validationErrors, err
+		}
+		if !m {
+			validationErrors = append(validationErrors, n.Finding(subject+" should "+desc))
+		}
+		return validationErrors, nil
+	}
+}
+
+func validateActionInputDescription(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.ActionInput.Description == "" {
+		validationErrors = append(validationErrors, n.Finding("Action input must have a description"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionOutputDescription(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.ActionOutput.Description == "" {
+		validationErrors = append(validationErrors, n.Finding("Action output must have a description"))
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowInputDescription(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.WorkflowInput.Description == "" {
+		validationErrors = append(validationErrors, n.Finding("Workflow input must have a description"))
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,67 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("NW501", "Workflow job name should follow the naming convention, kebab by default", validateJobName, NodeWorkflowJob)
+	registerBuiltin("NW502", "Env variable name should follow the naming convention, SCREAMING_SNAKE by default", validateJobEnv, NodeWorkflowJob)
+	registerBuiltin("EW203", "Job has invalid value in 'needs' field", validateJobNeeds, NodeWorkflowJob)
+	registerBuiltin("EW601", "Workflow job name should have either 'uses' or 'runs-on'", validateJobUsesOrRunsOn, NodeWorkflowJob)
+	registerBuiltin("EW602", "Workflow job should not have 'latest' in 'runs-on'", validateJobRunsOnLatest, NodeWorkflowJob)
+}
+
+func validateJobName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	m, desc, err := matchNaming(n, "NW501", config.NamingKebab, n.JobName)
+	if err != nil {
+		return validationErrors, err
+	}
+	if !m {
+		validationErrors = append(validationErrors, n.Finding("Workflow job name should "+desc))
+	}
+	return validationErrors, nil
+}
+
+func validateJobEnv(n *Node) ([]*finding.Finding, error) {
+	return validateEnvNames(n, "NW502", n.Job.Env)
+}
+
+func validateJobNeeds(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Job.Needs == nil {
+		return validationErrors, nil
+	}
+	for _, neededJob := range n.Job.GetNeeds() {
+		if n.Workflow.Jobs[neededJob] == nil {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Job '%s' has invalid value '%s' in 'needs' field", n.JobName, neededJob)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateJobUsesOrRunsOn(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Job.Uses == "" && n.Job.RunsOn.IsEmpty() {
+		validationErrors = append(validationErrors, n.Finding("Workflow job name should have either 'uses' or 'runs-on'"))
+	}
+	return validationErrors, nil
+}
+
+func validateJobRunsOnLatest(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Job.RunsOn == nil {
+		return validationErrors, nil
+	}
+	for _, label := range n.Job.RunsOn.Labels {
+		if strings.Contains(label, "latest") {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.Job.RunsOn.Line, "Workflow job should not have 'latest' in 'runs-on'"))
+		}
+	}
+	return validationErrors, nil
+}
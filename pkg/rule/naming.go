@@ -0,0 +1,12 @@
+package rule
+
+// matchNaming checks name against the naming convention configured for the rule with the code, and returns
+// description of the convention to be used in the finding.
+func matchNaming(n *Node, code string, defaultStyle string, name string) (bool, string, error) {
+	naming := n.Config.GetNaming(code, defaultStyle)
+	re, err := naming.Regexp()
+	if err != nil {
+		return false, "", err
+	}
+	return re.MatchString(name), naming.Description(), nil
+}
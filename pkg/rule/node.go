@@ -0,0 +1,153 @@
+package rule
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
+)
+
+type NodeKind int
+
+const (
+	NodeAction NodeKind = iota + 1
+	NodeActionInput
+	NodeActionOutput
+	NodeActionStep
+	NodeWorkflow
+	NodeWorkflowInput
+	NodeWorkflowJob
+	NodeWorkflowStep
+)
+
+// Node is a part of the parsed .github directory that is visited by rules.  Fields are set depending on the
+// Kind, eg. Action is set for NodeAction and all the nodes inside an action, Job and JobName are set for
+// NodeWorkflowJob and NodeWorkflowStep, and Name is the name of an input or output.  Placement is either 'call'
+// or 'dispatch' for a workflow input.  Config is never nil.
+type Node struct {
+	Kind          NodeKind
+	DotGithub     *dotgithub.DotGithub
+	Config        *config.Config
+	Action        *action.Action
+	ActionInput   *action.ActionInput
+	ActionOutput  *action.ActionOutput
+	Workflow      *workflow.Workflow
+	WorkflowInput *workflow.WorkflowInput
+	Placement     string
+	Job           *workflow.WorkflowJob
+	JobName       string
+	Step          *action.ActionStep
+	StepIndex     int
+	Name          string
+}
+
+// File returns path to the action or workflow file that the node is in.
+func (n *Node) File() string {
+	if n.Action != nil {
+		return n.Action.Path
+	}
+	if n.Workflow != nil {
+		return n.Workflow.Path
+	}
+	return ""
+}
+
+// Raw returns contents of the action or workflow file that the node is in.
+func (n *Node) Raw() []byte {
+	if n.Action != nil {
+		return n.Action.Raw
+	}
+	if n.Workflow != nil {
+		return n.Workflow.Raw
+	}
+	return nil
+}
+
+// Line returns line of the node in its file or 0 when node is the whole file.
+func (n *Node) Line() int {
+	switch n.Kind {
+	case NodeActionInput:
+		return n.ActionInput.Line
+	case NodeActionOutput:
+		return n.ActionOutput.Line
+	case NodeWorkflowInput:
+		return n.WorkflowInput.Line
+	case NodeWorkflowJob:
+		return n.Job.Line
+	case NodeActionStep, NodeWorkflowStep:
+		return n.Step.Line
+	}
+	return 0
+}
+
+// LineAt returns line number of a byte offset in the file that the node is in.
+func (n *Node) LineAt(offset int) int {
+	return bytes.Count(n.Raw()[:offset], []byte("\n")) + 1
+}
+
+// Subject returns human-readable location of the node, eg. 'workflow main.yml job build step 2'.
+func (n *Node) Subject() string {
+	switch n.Kind {
+	case NodeAction:
+		return "action " + n.Action.DirName
+	case NodeActionInput:
+		return "action " + n.Action.DirName + " input " + n.Name
+	case NodeActionOutput:
+		return "action " + n.Action.DirName + " output " + n.Name
+	case NodeActionStep:
+		return "action " + n.Action.DirName + " step " + strconv.Itoa(n.StepIndex)
+	case NodeWorkflow:
+		return "workflow " + n.Workflow.FileName
+	case NodeWorkflowInput:
+		return "workflow " + n.Workflow.FileName + " " + n.Placement + " input " + n.Name
+	case NodeWorkflowJob:
+		return "workflow " + n.Workflow.FileName + " job " + n.JobName
+	case NodeWorkflowStep:
+		return "workflow " + n.Workflow.FileName + " job " + n.JobName + " step " + strconv.Itoa(n.StepIndex)
+	}
+	return ""
+}
+
+// Finding returns a finding with description at the position of the node.
+func (n *Node) Finding(desc string) *finding.Finding {
+	return n.FindingAtLine(n.Line(), desc)
+}
+
+// FindingAtLine returns a finding with description at specific line of the file that the node is in.
+func (n *Node) FindingAtLine(line int, desc string) *finding.Finding {
+	return &finding.Finding{
+		File:        n.File(),
+		Line:        line,
+		Name:        n.Subject(),
+		Description: desc,
+	}
+}
+
+// LineOf returns line of the first occurrence of s in the file, starting from the line of the node.  When s is
+// not found, eg. because it was in a folded YAML string, line of the node is returned.
+func (n *Node) LineOf(s string) int {
+	return n.LineOfFrom(n.Line(), s)
+}
+
+// LineOfFrom returns line of the first occurrence of s in the file, starting from the line, or the line itself
+// when s is not found.
+func (n *Node) LineOfFrom(line int, s string) int {
+	raw := n.Raw()
+	start := 0
+	for l := 1; l < line && start < len(raw); l++ {
+		i := bytes.IndexByte(raw[start:], '\n')
+		if i == -1 {
+			break
+		}
+		start += i + 1
+	}
+	i := bytes.Index(raw[start:], []byte(s))
+	if i == -1 {
+		return line
+	}
+	return n.LineAt(start + i)
+}
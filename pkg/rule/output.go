@@ -0,0 +1,118 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EA511", "Output of composite action must have a value", validateActionOutputValueExists, NodeActionOutput)
+	registerBuiltin("EA512", "Output value refers to step that does not exist", validateActionOutputValueSteps, NodeActionOutput)
+	registerBuiltin("EA513", "Output value refers to step output that does not exist", validateActionOutputValueStepOutputs, NodeActionOutput)
+	registerBuiltin("WA511", "Output value is ignored as action is not composite", validateActionOutputValueIgnored, NodeActionOutput)
+	registerBuiltin("WA512", "Output value of composite action does not refer to any step output", validateActionOutputValueRefersStep, NodeActionOutput)
+}
+
+func isCompositeAction(a *action.Action) bool {
+	return a.Runs != nil && a.Runs.Using == runsComposite
+}
+
+// outputValueStep matches a step output referenced in an expression, eg. '${{ steps.id.outputs.name }}'.
+var outputValueStep = regexp.MustCompile(`\${{[^}]*?steps\.([a-zA-Z0-9\-_]+)\.outputs\.([a-zA-Z0-9\-_]+)`)
+
+// findOutputValueSteps returns pairs of step id and output referenced in value of the output.
+func findOutputValueSteps(value string) [][2]string {
+	var called [][2]string
+	for _, f := range outputValueStep.FindAllStringSubmatch(value, -1) {
+		called = append(called, [2]string{f[1], f[2]})
+	}
+	return called
+}
+
+// isStepOutputSet returns true when the step with the id sets the output.  False is returned as the second
+// value when the outputs of the step cannot be known, eg. when it runs a docker image, or calls an action that
+// cannot be found or downloaded.
+func isStepOutputSet(n *Node, id string, output string) (bool, bool, error) {
+	for _, s := range n.Action.Runs.Steps {
+		if s == nil || s.Id != id {
+			continue
+		}
+		var a *action.Action
+		switch {
+		case s.Uses == "":
+			return s.GetRunOutput(output) >= 0, true, nil
+		case dotgithub.GetLocalActionName(s.Uses) != "":
+			a = n.DotGithub.GetAction(dotgithub.GetLocalActionName(s.Uses))
+		case isExternalActionUses(s.Uses):
+			err := n.DotGithub.DownloadExternalAction(s.Uses)
+			if err != nil {
+				return false, false, err
+			}
+			a = n.DotGithub.GetExternalAction(s.Uses)
+		}
+		if a == nil {
+			return false, false, nil
+		}
+		_, ok := a.Outputs[output]
+		return ok, true, nil
+	}
+	return false, false, nil
+}
+
+func validateActionOutputValueExists(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if isCompositeAction(n.Action) && n.ActionOutput.Value == "" {
+		validationErrors = append(validationErrors, n.Finding("Output of composite action must have a value"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionOutputValueIgnored(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs != nil && !isCompositeAction(n.Action) && n.ActionOutput.Value != "" {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Output value is ignored as action uses '%s' and not 'composite'", n.Action.Runs.Using)))
+	}
+	return validationErrors, nil
+}
+
+func validateActionOutputValueRefersStep(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if isCompositeAction(n.Action) && n.ActionOutput.Value != "" && len(findOutputValueSteps(n.ActionOutput.Value)) == 0 {
+		validationErrors = append(validationErrors, n.Finding("Output value should refer to a step output like '${{ steps.<id>.outputs.<name> }}'"))
+	}
+	return validationErrors, nil
+}
+
+func validateActionOutputValueSteps(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isCompositeAction(n.Action) {
+		return validationErrors, nil
+	}
+	for _, called := range findOutputValueSteps(n.ActionOutput.Value) {
+		if !n.Action.Runs.IsStepExist(called[0]) {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Output value refers to step with id '%s' that does not exist", called[0])))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateActionOutputValueStepOutputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isCompositeAction(n.Action) {
+		return validationErrors, nil
+	}
+	for _, called := range findOutputValueSteps(n.ActionOutput.Value) {
+		set, known, err := isStepOutputSet(n, called[0], called[1])
+		if err != nil {
+			return validationErrors, err
+		}
+		if known && !set {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Output value refers to step with id '%s' output '%s' that does not exist", called[0], called[1])))
+		}
+	}
+	return validationErrors, nil
+}
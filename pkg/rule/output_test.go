@@ -0,0 +1,15 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestOutputRules(t *testing.T) {
+	testFixture(t, "output", nil, []fixtureCase{
+		{"EA511", []string{"EA511:26"}},
+		{"EA512", []string{"EA512:23"}},
+		{"EA513", []string{"EA513:8", "EA513:14"}},
+		{"WA511", []string{"WA511:5"}},
+		{"WA512", []string{"WA512:28"}},
+	})
+}
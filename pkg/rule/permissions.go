@@ -0,0 +1,247 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
+)
+
+func init() {
+	registerBuiltin("EW911", "Permission scope is invalid", validatePermissionScopes, NodeWorkflow, NodeWorkflowJob)
+	registerBuiltin("EW912", "Permission value is invalid", validatePermissionValues, NodeWorkflow, NodeWorkflowJob)
+	registerBuiltin("WW911", "Workflow and its jobs have no permissions declared and inherit the default ones", validatePermissionsDeclared, NodeWorkflow)
+	registerBuiltin("WW912", "Permissions are set to write-all", validatePermissionsWriteAll, NodeWorkflow, NodeWorkflowJob)
+	registerBuiltin("WW913", "Job has 'contents: write' permission but does not seem to push or release", validateJobContentsWrite, NodeWorkflowJob)
+	registerBuiltin("WW914", "Job calls an action that uses OIDC but has no 'id-token: write' permission", validateJobIdTokenWrite, NodeWorkflowJob)
+}
+
+var permissionScopes = map[string]bool{
+	"actions":             true,
+	"attestations":        true,
+	"checks":              true,
+	"contents":            true,
+	"deployments":         true,
+	"discussions":         true,
+	"id-token":            true,
+	"issues":              true,
+	"models":              true,
+	"packages":            true,
+	"pages":               true,
+	"pull-requests":       true,
+	"repository-projects": true,
+	"security-events":     true,
+	"statuses":            true,
+}
+
+// writingActions are actions that push to the repository or create releases.
+var writingActions = []string{
+	"ad-m/github-push-action@",
+	"actions/create-release@",
+	"actions/upload-release-asset@",
+	"changesets/action@",
+	"endbug/add-and-commit@",
+	"google-github-actions/release-please-action@",
+	"googleapis/release-please-action@",
+	"goreleaser/goreleaser-action@",
+	"jamesives/github-pages-deploy-action@",
+	"ncipollo/release-action@",
+	"peaceiris/actions-gh-pages@",
+	"peter-evans/create-pull-request@",
+	"softprops/action-gh-release@",
+	"stefanzweifel/git-auto-commit-action@",
+}
+
+// writingCommands are commands that push commits or tags, or create releases.  Git may get global options,
+// eg. 'git -C dir push'.  Tags created with 'git tag' are
+// only pushed by 'git push', and an API call can do anything, so neither is considered writing on its own.
+var writingCommands = regexp.MustCompile(`\bgit[ ]+(-[^ ]+[ ]+([^- ][^ ]*[ ]+)?)*push\b|\bgh[ ]+release[ ]+(create|delete|edit|upload)\b`)
+
+// oidcActions are actions that request an OIDC token to authenticate with a cloud provider or a registry,
+// along with a function telling whether the step uses OIDC, as some of them can authenticate with static
+// credentials as well.
+var oidcActions = map[string]func(with map[string]string) bool{
+	"actions/attest-build-provenance@": func(with map[string]string) bool { return true },
+	"actions/attest-sbom@":             func(with map[string]string) bool { return true },
+	"actions/attest@":                  func(with map[string]string) bool { return true },
+	"aws-actions/configure-aws-credentials@": func(with map[string]string) bool {
+		return with["role-to-assume"] != "" && with["aws-access-key-id"] == "" && with["web-identity-token-file"] == ""
+	},
+	"azure/login@": func(with map[string]string) bool {
+		return with["client-id"] != "" && with["creds"] == ""
+	},
+	"google-github-actions/auth@": func(with map[string]string) bool {
+		return with["workload_identity_provider"] != ""
+	},
+	"hashicorp/vault-action@": func(with map[string]string) bool {
+		return with["method"] == "jwt" && with["jwtToken"] == ""
+	},
+	"pypa/gh-action-pypi-publish@": func(with map[string]string) bool {
+		return with["password"] == ""
+	},
+}
+
+// getPermissions returns permissions of the node - the workflow ones or, for a job, its own permissions or the
+// workflow ones when job does not have any.  Nil means that permissions are not declared.
+func getPermissions(n *Node) *workflow.WorkflowPermissions {
+	if n.Kind == NodeWorkflowJob && n.Job.Permissions != nil {
+		return n.Job.Permissions
+	}
+	return n.Workflow.Permissions
+}
+
+// getDeclaredPermissions returns permissions declared directly in the workflow or the job.
+func getDeclaredPermissions(n *Node) *workflow.WorkflowPermissions {
+	if n.Kind == NodeWorkflowJob {
+		return n.Job.Permissions
+	}
+	return n.Workflow.Permissions
+}
+
+func validatePermissionScopes(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	p := getDeclaredPermissions(n)
+	if p == nil {
+		return validationErrors, nil
+	}
+	for _, scope := range sortedKeys(p.Scopes) {
+		if !permissionScopes[scope] {
+			validationErrors = append(validationErrors, n.FindingAtLine(p.ScopeLines[scope], fmt.Sprintf("Permission scope '%s' is invalid", scope)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validatePermissionValues(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	p := getDeclaredPermissions(n)
+	if p == nil {
+		return validationErrors, nil
+	}
+	if p.All != "" && p.All != workflow.PermissionsReadAll && p.All != workflow.PermissionsWriteAll {
+		validationErrors = append(validationErrors, n.FindingAtLine(p.Line, fmt.Sprintf("Permissions value '%s' is invalid, should be 'read-all', 'write-all' or a map of scopes", p.All)))
+	}
+	for _, scope := range sortedKeys(p.Scopes) {
+		v := p.Scopes[scope]
+		if v != workflow.PermissionRead && v != workflow.PermissionWrite && v != workflow.PermissionNone {
+			validationErrors = append(validationErrors, n.FindingAtLine(p.ScopeLines[scope], fmt.Sprintf("Permission '%s' of scope '%s' is invalid, should be 'read', 'write' or 'none'", v, scope)))
+		}
+	}
+	return validationErrors, nil
+}
+
+// validatePermissionsDeclared reports the workflow once, listing all the jobs that inherit default permissions.
+// Jobs are fine when the workflow declares permissions.
+func validatePermissionsDeclared(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Workflow.Permissions != nil {
+		return validationErrors, nil
+	}
+	var jobs []string
+	for _, name := range sortedKeys(n.Workflow.Jobs) {
+		if n.Workflow.Jobs[name] != nil && n.Workflow.Jobs[name].Permissions == nil {
+			jobs = append(jobs, "'"+name+"'")
+		}
+	}
+	switch {
+	case len(jobs) == 1:
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Workflow has no 'permissions' and neither has job %s - it inherits default permissions of the repository", jobs[0])))
+	case len(jobs) > 1:
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Workflow has no 'permissions' and neither have jobs %s - they inherit default permissions of the repository", strings.Join(jobs, ", "))))
+	}
+	return validationErrors, nil
+}
+
+func validatePermissionsWriteAll(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	p := getDeclaredPermissions(n)
+	if p != nil && p.All == workflow.PermissionsWriteAll {
+		validationErrors = append(validationErrors, n.FindingAtLine(p.Line, "Permissions are set to 'write-all' - grant write access only to the scopes that are needed"))
+	}
+	return validationErrors, nil
+}
+
+func hasUsesPrefix(uses string, prefixes []string) bool {
+	uses = strings.ToLower(uses)
+	for _, p := range prefixes {
+		if strings.HasPrefix(uses, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayWriteContents returns true when any of the steps may push or release, or when it cannot be said, eg. when
+// the token is passed to an unknown action.  Local actions are checked recursively.
+func mayWriteContents(n *Node, steps []*action.ActionStep, visited map[string]bool) bool {
+	for _, s := range steps {
+		if s == nil {
+			continue
+		}
+		if s.Run != "" && writingCommands.MatchString(s.Run) {
+			return true
+		}
+		if hasUsesPrefix(s.Uses, writingActions) || hasUsesPrefix(s.Uses, []string{"actions/github-script@"}) {
+			return true
+		}
+		if isLocalActionUses(s.Uses) {
+			name := strings.Replace(s.Uses, "./.github/actions/", "", -1)
+			a := n.DotGithub.GetAction(name)
+			if a == nil || visited[name] {
+				continue
+			}
+			visited[name] = true
+			if a.Runs == nil || a.Runs.Using != "composite" || mayWriteContents(n, a.Runs.Steps, visited) {
+				return true
+			}
+			continue
+		}
+		if s.Uses != "" {
+			for _, v := range s.With {
+				if strings.Contains(v, "github.token") || strings.Contains(v, "secrets.GITHUB_TOKEN") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func validateJobContentsWrite(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	p := getPermissions(n)
+	if p == nil || p.Scopes["contents"] != workflow.PermissionWrite || n.Job.Uses != "" {
+		return validationErrors, nil
+	}
+	if !mayWriteContents(n, n.Job.Steps, map[string]bool{}) {
+		line := p.ScopeLines["contents"]
+		if p != n.Job.Permissions {
+			line = n.Line()
+		}
+		validationErrors = append(validationErrors, n.FindingAtLine(line, "Job has 'contents: write' permission but does not seem to push or release - use 'contents: read' instead"))
+	}
+	return validationErrors, nil
+}
+
+func validateJobIdTokenWrite(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	p := getPermissions(n)
+	if p != nil && p.Get("id-token") == workflow.PermissionWrite {
+		return validationErrors, nil
+	}
+	for i, s := range n.Job.Steps {
+		if s == nil {
+			continue
+		}
+		for _, prefix := range sortedKeys(oidcActions) {
+			if hasUsesPrefix(s.Uses, []string{prefix}) && oidcActions[prefix](s.With) {
+				validationErrors = append(validationErrors, n.FindingAtLine(s.Line, fmt.Sprintf("Step %d calls '%s' that uses OIDC, but job has no 'id-token: write' permission", i, s.Uses)))
+				break
+			}
+		}
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,16 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestPermissionsRules(t *testing.T) {
+	testFixture(t, "permissions", nil, []fixtureCase{
+		{"EW911", []string{"EW911:7"}},
+		{"EW912", []string{"EW912:5", "EW912:6", "EW912:3"}},
+		{"WW911", []string{"WW911:0"}},
+		{"WW912", []string{"WW912:11"}},
+		{"WW913", []string{"WW913:25"}},
+		{"WW914", []string{"WW914:46"}},
+	})
+}
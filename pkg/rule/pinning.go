@@ -0,0 +1,48 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("WA831", "External action is not pinned to a full commit SHA", validateUsesPinned, NodeActionStep)
+	registerBuiltin("WW831", "External action or reusable workflow is not pinned to a full commit SHA", validateUsesPinned, NodeWorkflowStep, NodeWorkflowJob)
+}
+
+var (
+	remoteUses = regexp.MustCompile(`^([a-zA-Z0-9\-_\.]+\/[a-zA-Z0-9\-_\.]+)(\/[^@]*)?@(.+)$`)
+	commitSHA  = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// parseRemoteUses splits 'owner/repo/path@ref' of an external action or a reusable workflow into 'owner/repo'
+// and the ref.  False is returned for local actions and workflows, and docker images.
+func parseRemoteUses(uses string) (string, string, bool) {
+	m := remoteUses.FindStringSubmatch(uses)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[3], true
+}
+
+func validateUsesPinned(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	uses := ""
+	subject := "External action"
+	if n.Kind == NodeWorkflowJob {
+		uses = n.Job.Uses
+		subject = "Reusable workflow"
+	} else {
+		uses = n.Step.Uses
+	}
+	repo, ref, ok := parseRemoteUses(uses)
+	if !ok || n.Config.IsUnpinnedAllowed(repo) {
+		return validationErrors, nil
+	}
+	if !commitSHA.MatchString(ref) {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("%s '%s' should be pinned to a full commit SHA instead of '%s'", subject, uses, ref)))
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,48 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+)
+
+func TestParseRemoteUses(t *testing.T) {
+	tests := []struct {
+		uses string
+		repo string
+		ref  string
+		ok   bool
+	}{
+		{"actions/checkout@v4", "actions/checkout", "v4", true},
+		{"owner/repo/path/to/action@main", "owner/repo", "main", true},
+		{"owner/repo/.github/workflows/build.yml@v1", "owner/repo", "v1", true},
+		{"./.github/actions/build", "", "", false},
+		{"docker://alpine:3", "", "", false},
+		{"owner/repo", "", "", false},
+	}
+	for _, tt := range tests {
+		repo, ref, ok := parseRemoteUses(tt.uses)
+		if repo != tt.repo || ref != tt.ref || ok != tt.ok {
+			t.Errorf("parseRemoteUses(%q) = %q, %q, %v, want %q, %q, %v", tt.uses, repo, ref, ok, tt.repo, tt.ref, tt.ok)
+		}
+	}
+}
+
+func TestPinningRules(t *testing.T) {
+	testFixture(t, "pinning", nil, []fixtureCase{
+		{"WA831", []string{"WA831:6", "WA831:7"}},
+		{"WW831", []string{"WW831:7", "WW831:9", "WW831:10", "WW831:12", "WW831:16"}},
+	})
+}
+
+func TestPinningRulesAllowedOwners(t *testing.T) {
+	cfg := &config.Config{
+		Pinning: &config.Pinning{
+			AllowedOwners: []string{"actions/*", "someone"},
+		},
+	}
+	testFixture(t, "pinning", cfg, []fixtureCase{
+		{"WA831", []string{}},
+		{"WW831", []string{}},
+	})
+}
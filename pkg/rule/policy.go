@@ -0,0 +1,41 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EA832", "Action or docker image is not allowed by the configuration", validateUsesAllowed, NodeActionStep)
+	registerBuiltin("EW832", "Action, reusable workflow or docker image is not allowed by the configuration", validateUsesAllowed, NodeWorkflowStep, NodeWorkflowJob)
+}
+
+func validateUsesAllowed(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	uses := ""
+	subject := "Action"
+	if n.Kind == NodeWorkflowJob {
+		uses = n.Job.Uses
+		subject = "Reusable workflow"
+	} else {
+		uses = n.Step.Uses
+	}
+	repo := ""
+	if strings.HasPrefix(uses, "docker://") {
+		name, _ := splitImage(uses)
+		repo = "docker://" + name
+		subject = "Docker image"
+	} else {
+		r, _, ok := parseRemoteUses(uses)
+		if !ok {
+			return validationErrors, nil
+		}
+		repo = r
+	}
+	if !n.Config.IsActionAllowed(repo) {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("%s '%s' is not allowed by the configuration", subject, uses)))
+	}
+	return validationErrors, nil
+}
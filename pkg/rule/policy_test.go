@@ -0,0 +1,60 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+)
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{"alpine", "alpine", ""},
+		{"alpine:3", "alpine", "3"},
+		{"docker://alpine:3", "alpine", "3"},
+		{"localhost:5000/owner/image", "localhost:5000/owner/image", ""},
+		{"localhost:5000/owner/image:1.0", "localhost:5000/owner/image", "1.0"},
+		{"ghcr.io/owner/image:1@sha256:abc", "ghcr.io/owner/image", "1"},
+		{"ghcr.io/owner/image@sha256:abc", "ghcr.io/owner/image", ""},
+	}
+	for _, tt := range tests {
+		name, tag := splitImage(tt.image)
+		if name != tt.name || tag != tt.tag {
+			t.Errorf("splitImage(%q) = %q, %q, want %q, %q", tt.image, name, tag, tt.name, tt.tag)
+		}
+	}
+}
+
+func TestPolicyRulesAllowed(t *testing.T) {
+	cfg := &config.Config{
+		Actions: &config.Actions{
+			Allowed: []string{"actions", "someone/*", "docker://alpine"},
+		},
+	}
+	testFixture(t, "policy", cfg, []fixtureCase{
+		{"EA832", []string{"EA832:7", "EA832:8"}},
+		{"EW832", []string{"EW832:9", "EW832:11", "EW832:16"}},
+	})
+}
+
+func TestPolicyRulesDenied(t *testing.T) {
+	cfg := &config.Config{
+		Actions: &config.Actions{
+			Denied: []string{"evil", "docker://*/evil/*"},
+		},
+	}
+	testFixture(t, "policy", cfg, []fixtureCase{
+		{"EA832", []string{"EA832:7", "EA832:8"}},
+		{"EW832", []string{"EW832:9", "EW832:11", "EW832:16"}},
+	})
+}
+
+func TestPolicyRulesWithoutConfig(t *testing.T) {
+	testFixture(t, "policy", nil, []fixtureCase{
+		{"EA832", []string{}},
+		{"EW832", []string{}},
+	})
+}
@@ -0,0 +1,116 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EW901", "Workflow triggered by pull_request_target or workflow_run runs untrusted code with secrets or write token", validateJobPrivilegedUntrustedCode, NodeWorkflowJob)
+}
+
+// privilegedEvents run in the context of the base repository, with access to secrets and a token that can write,
+// even though they are caused by a pull request from a fork.
+var privilegedEvents = []string{"pull_request_target", "workflow_run"}
+
+// untrustedRefContexts are the contexts pointing to code of a pull request or of the run that triggered the
+// workflow.
+var untrustedRefContexts = regexp.MustCompile(`github\.event\.pull_request\.head\.|github\.head_ref|github\.event\.workflow_run\.(head_|pull_requests)|refs/pull/`)
+
+var (
+	privilegePrCheckout   = regexp.MustCompile(`gh[ ]+pr[ ]+checkout`)
+	privilegeGitCheckout  = regexp.MustCompile(`git[ ]+(fetch|checkout|switch|pull)`)
+	privilegeRunDownload  = regexp.MustCompile(`gh[ ]+run[ ]+download`)
+	privilegeSecretAccess = regexp.MustCompile(`\${{[ ]*secrets\.([a-zA-Z0-9\-_]+)[ ]*}}`)
+)
+
+// getUntrustedCodeSource returns description of how the step gets untrusted code - by checking out the head of
+// a pull request or downloading artifacts - or an empty string when it does not.
+func getUntrustedCodeSource(s *action.ActionStep) string {
+	uses := strings.ToLower(s.Uses)
+	if strings.HasPrefix(uses, "actions/checkout@") {
+		if untrustedRefContexts.MatchString(s.With["ref"]) || untrustedRefContexts.MatchString(s.With["repository"]) {
+			return "checks out the pull request head"
+		}
+		return ""
+	}
+	if strings.HasPrefix(uses, "actions/download-artifact@") || strings.HasPrefix(uses, "dawidd6/action-download-artifact@") {
+		return "downloads artifacts"
+	}
+	if s.Run == "" {
+		return ""
+	}
+	if privilegePrCheckout.MatchString(s.Run) {
+		return "checks out the pull request head"
+	}
+	if privilegeGitCheckout.MatchString(s.Run) && untrustedRefContexts.MatchString(s.Run) {
+		return "checks out the pull request head"
+	}
+	if privilegeRunDownload.MatchString(s.Run) {
+		return "downloads artifacts"
+	}
+	return ""
+}
+
+// isRunningCode returns true when the step runs code from the workspace, ie. it has a script or calls a local
+// action, which is taken from the checked out code.
+func isRunningCode(s *action.ActionStep) bool {
+	return s.Run != "" || isLocalActionUses(s.Uses)
+}
+
+// getStepPrivileges returns secrets available to the step, followed by the GITHUB_TOKEN when it can write, which
+// is assumed when permissions are not declared.
+func getStepPrivileges(n *Node, s *action.ActionStep) []string {
+	texts := []string{s.Run}
+	for _, env := range []map[string]string{n.Workflow.Env, n.Job.Env, s.Env, s.With} {
+		for _, k := range sortedKeys(env) {
+			texts = append(texts, env[k])
+		}
+	}
+	var privileges []string
+	found := map[string]bool{}
+	for _, t := range texts {
+		for _, f := range privilegeSecretAccess.FindAllStringSubmatch(t, -1) {
+			if f[1] != "GITHUB_TOKEN" && !found[f[1]] {
+				found[f[1]] = true
+				privileges = append(privileges, "secrets."+f[1])
+			}
+		}
+	}
+	p := getPermissions(n)
+	if p == nil || p.HasWrite() {
+		privileges = append(privileges, "GITHUB_TOKEN")
+	}
+	return privileges
+}
+
+func validateJobPrivilegedUntrustedCode(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, event := range privilegedEvents {
+		if !n.Workflow.On.IsEvent(event) {
+			continue
+		}
+		sourceIndex := -1
+		source := ""
+		for i, s := range n.Job.Steps {
+			if s == nil {
+				continue
+			}
+			if sourceIndex != -1 && isRunningCode(s) {
+				privileges := getStepPrivileges(n, s)
+				if len(privileges) > 0 {
+					validationErrors = append(validationErrors, n.FindingAtLine(s.Line, fmt.Sprintf("Workflow triggered by '%s' %s in step %d and runs it in step %d with %s available", event, source, sourceIndex, i, strings.Join(privileges, ", "))))
+				}
+			}
+			if src := getUntrustedCodeSource(s); src != "" {
+				sourceIndex = i
+				source = src
+			}
+		}
+	}
+	return validationErrors, nil
+}
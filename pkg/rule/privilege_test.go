@@ -0,0 +1,11 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestPrivilegeRules(t *testing.T) {
+	testFixture(t, "privilege", nil, []fixtureCase{
+		{"EW901", []string{"EW901:13", "EW901:22", "EW901:29", "EW901:10", "EW901:19", "EW901:26"}},
+	})
+}
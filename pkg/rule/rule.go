@@ -0,0 +1,117 @@
+// Package rule contains checks that are run against the parsed .github directory.  Each check is a Rule with
+// a unique code, and it is added to a Registry that visits all the actions, workflows, jobs, steps and their
+// inputs and outputs.
+package rule
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+// Rule is a single check.  Visit is called for every node of the parsed .github directory and it should return
+// findings for the node.  Code and Severity of returned findings are set by the Registry to the ones of the rule.
+type Rule interface {
+	Code() string
+	Description() string
+	Severity() finding.Severity
+	Visit(n *Node) ([]*finding.Finding, error)
+}
+
+// Registry is a set of rules with unique codes.
+type Registry struct {
+	rules map[string]Rule
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		rules: map[string]Rule{},
+	}
+}
+
+// Builtin returns a new registry with all the built-in rules.
+func Builtin() *Registry {
+	r := NewRegistry()
+	for _, b := range builtins {
+		_ = r.Register(b)
+	}
+	return r
+}
+
+func (r *Registry) Register(rule Rule) error {
+	if rule.Code() == "" {
+		return fmt.Errorf("Rule code cannot be empty")
+	}
+	if r.rules[rule.Code()] != nil {
+		return fmt.Errorf("Rule with code %s is already registered", rule.Code())
+	}
+	r.rules[rule.Code()] = rule
+	return nil
+}
+
+func (r *Registry) Get(code string) Rule {
+	return r.rules[code]
+}
+
+// Rules returns all the registered rules sorted by code.
+func (r *Registry) Rules() []Rule {
+	rules := make([]Rule, 0, len(r.rules))
+	for _, code := range sortedKeys(r.rules) {
+		rules = append(rules, r.rules[code])
+	}
+	return rules
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// builtin is a Rule that visits only the nodes of specific kinds.  Rules without visit function are the ones
+// reported outside of the Registry, eg. when a file cannot be parsed, and they are here to be listed.
+type builtin struct {
+	code        string
+	description string
+	kinds       []NodeKind
+	visit       func(n *Node) ([]*finding.Finding, error)
+}
+
+var builtins []*builtin
+
+func registerBuiltin(code string, description string, visit func(n *Node) ([]*finding.Finding, error), kinds ...NodeKind) {
+	builtins = append(builtins, &builtin{
+		code:        code,
+		description: description,
+		kinds:       kinds,
+		visit:       visit,
+	})
+}
+
+func (b *builtin) Code() string {
+	return b.code
+}
+
+func (b *builtin) Description() string {
+	return b.description
+}
+
+func (b *builtin) Severity() finding.Severity {
+	return finding.SeverityOf(b.code)
+}
+
+func (b *builtin) Visit(n *Node) ([]*finding.Finding, error) {
+	if b.visit == nil {
+		return nil, nil
+	}
+	for _, k := range b.kinds {
+		if k == n.Kind {
+			return b.visit(n)
+		}
+	}
+	return nil, nil
+}
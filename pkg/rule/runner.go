@@ -0,0 +1,111 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EW603", "Runner label in 'runs-on' is not a GitHub-hosted runner nor a configured self-hosted one", validateJobRunnerLabels, NodeWorkflowJob)
+	registerBuiltin("EW604", "GitHub-hosted runner label in 'runs-on' is combined with other labels", validateJobRunnerLabelsCombined, NodeWorkflowJob)
+}
+
+// githubHostedRunners are labels of GitHub-hosted runners, including larger macOS ones.
+var githubHostedRunners = map[string]bool{
+	"ubuntu-latest":       true,
+	"ubuntu-24.04":        true,
+	"ubuntu-22.04":        true,
+	"ubuntu-24.04-arm":    true,
+	"ubuntu-22.04-arm":    true,
+	"ubuntu-slim":         true,
+	"windows-latest":      true,
+	"windows-2025":        true,
+	"windows-2022":        true,
+	"windows-11-arm":      true,
+	"macos-latest":        true,
+	"macos-26":            true,
+	"macos-15":            true,
+	"macos-14":            true,
+	"macos-13":            true,
+	"macos-latest-large":  true,
+	"macos-26-large":      true,
+	"macos-15-large":      true,
+	"macos-14-large":      true,
+	"macos-13-large":      true,
+	"macos-latest-xlarge": true,
+	"macos-26-xlarge":     true,
+	"macos-15-xlarge":     true,
+	"macos-14-xlarge":     true,
+	"macos-13-xlarge":     true,
+}
+
+// retiredRunners are labels of GitHub-hosted runners that were removed, so jobs using them never start.
+var retiredRunners = map[string]bool{
+	"ubuntu-16.04": true,
+	"ubuntu-18.04": true,
+	"ubuntu-20.04": true,
+	"windows-2016": true,
+	"windows-2019": true,
+	"macos-10.15":  true,
+	"macos-11":     true,
+	"macos-12":     true,
+}
+
+// selfHostedDefaultLabels are added by GitHub to every self-hosted runner.
+var selfHostedDefaultLabels = map[string]bool{
+	"self-hosted": true,
+	"linux":       true,
+	"windows":     true,
+	"macos":       true,
+	"x64":         true,
+	"arm":         true,
+	"arm64":       true,
+}
+
+// getRunnerLabels returns labels from 'runs-on' that are not expressions.
+func getRunnerLabels(n *Node) []string {
+	var labels []string
+	if n.Job.RunsOn == nil {
+		return labels
+	}
+	for _, l := range n.Job.RunsOn.Labels {
+		if !strings.Contains(l, "${{") {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+func validateJobRunnerLabels(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, label := range getRunnerLabels(n) {
+		l := strings.ToLower(label)
+		if githubHostedRunners[l] || selfHostedDefaultLabels[l] || n.Config.IsSelfHostedRunnerLabel(label) {
+			continue
+		}
+		desc := fmt.Sprintf("Runner label '%s' in 'runs-on' is not a GitHub-hosted runner nor a configured self-hosted one", label)
+		if retiredRunners[l] {
+			desc = fmt.Sprintf("Runner label '%s' in 'runs-on' is a retired GitHub-hosted runner", label)
+		}
+		validationErrors = append(validationErrors, n.FindingAtLine(n.Job.RunsOn.Line, desc))
+	}
+	return validationErrors, nil
+}
+
+// validateJobRunnerLabelsCombined checks lists of labels, as a runner must have all of them, and GitHub-hosted
+// runners have a single label.  Group of larger runners is an exception, as its runners can have such label.
+func validateJobRunnerLabelsCombined(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	labels := getRunnerLabels(n)
+	if len(labels) < 2 || n.Job.RunsOn.Group != "" {
+		return validationErrors, nil
+	}
+	for _, label := range labels {
+		if githubHostedRunners[strings.ToLower(label)] && !n.Config.IsSelfHostedRunnerLabel(label) {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.Job.RunsOn.Line, fmt.Sprintf("GitHub-hosted runner label '%s' in 'runs-on' is combined with other labels, so no runner matches it", label)))
+		}
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,26 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+)
+
+func TestRunnerRules(t *testing.T) {
+	testFixture(t, "runner", nil, []fixtureCase{
+		{"EW603", []string{"EW603:13", "EW603:17", "EW603:21", "EW603:25", "EW603:30"}},
+		{"EW604", []string{"EW604:25"}},
+	})
+}
+
+func TestRunnerRulesSelfHostedLabels(t *testing.T) {
+	cfg := &config.Config{
+		Runners: &config.Runners{
+			Labels: []string{"gpu-*"},
+		},
+	}
+	testFixture(t, "runner", cfg, []fixtureCase{
+		{"EW603", []string{"EW603:13"}},
+		{"EW604", []string{"EW604:25"}},
+	})
+}
@@ -0,0 +1,154 @@
+package rule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EA871", "Action runtime in 'runs.using' is invalid", validateActionRunsUsing, NodeAction)
+	registerBuiltin("WA871", "Action runtime in 'runs.using' is deprecated", validateActionRunsUsingDeprecated, NodeAction)
+	registerBuiltin("EA872", "Field required by the action runtime is missing in 'runs'", validateActionRunsRequired, NodeAction)
+	registerBuiltin("EA873", "Field in 'runs' is not used by the action runtime", validateActionRunsUnused, NodeAction)
+	registerBuiltin("EA874", "File referenced in 'runs' does not exist in the action directory", validateActionRunsFiles, NodeAction)
+}
+
+const (
+	runsComposite = "composite"
+	runsDocker    = "docker"
+)
+
+// nodeRuntimes are the JavaScript runtimes, with true for the deprecated ones.
+var nodeRuntimes = map[string]bool{
+	"node12": true,
+	"node16": true,
+	"node20": false,
+	"node24": false,
+}
+
+// runsFields returns names of fields set in 'runs', except 'using', along with the runtimes using them.
+func runsFields(n *Node) map[string]string {
+	r := n.Action.Runs
+	fields := map[string]string{}
+	set := func(name string, isSet bool, runtime string) {
+		if isSet {
+			fields[name] = runtime
+		}
+	}
+	set("main", r.Main != "", "node")
+	set("pre", r.Pre != "", "node")
+	set("post", r.Post != "", "node")
+	set("pre-if", r.PreIf != "", "node and docker")
+	set("post-if", r.PostIf != "", "node and docker")
+	set("image", r.Image != "", runsDocker)
+	set("args", len(r.Args) > 0, runsDocker)
+	set("entrypoint", r.Entrypoint != "", runsDocker)
+	set("pre-entrypoint", r.PreEntrypoint != "", runsDocker)
+	set("post-entrypoint", r.PostEntrypoint != "", runsDocker)
+	set("env", len(r.Env) > 0, runsDocker)
+	set("steps", len(r.Steps) > 0, runsComposite)
+	return fields
+}
+
+// getRuntimeKind returns 'node', 'docker' or 'composite', or an empty string for an invalid runtime.
+func getRuntimeKind(using string) string {
+	if _, ok := nodeRuntimes[using]; ok {
+		return "node"
+	}
+	if using == runsDocker || using == runsComposite {
+		return using
+	}
+	return ""
+}
+
+func validateActionRunsUsing(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs == nil {
+		validationErrors = append(validationErrors, n.Finding("Action does not have 'runs'"))
+		return validationErrors, nil
+	}
+	if getRuntimeKind(n.Action.Runs.Using) == "" {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOfFrom(n.Action.Runs.Line, "using:"), fmt.Sprintf("Action runtime '%s' in 'runs.using' is invalid - use one of node20, node24, docker or composite", n.Action.Runs.Using)))
+	}
+	return validationErrors, nil
+}
+
+func validateActionRunsUsingDeprecated(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs != nil && nodeRuntimes[n.Action.Runs.Using] {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOfFrom(n.Action.Runs.Line, "using:"), fmt.Sprintf("Action runtime '%s' is deprecated - use node20 or newer", n.Action.Runs.Using)))
+	}
+	return validationErrors, nil
+}
+
+func validateActionRunsRequired(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs == nil {
+		return validationErrors, nil
+	}
+	required := map[string]string{"node": "main", runsDocker: "image", runsComposite: "steps"}
+	kind := getRuntimeKind(n.Action.Runs.Using)
+	if kind == "" {
+		return validationErrors, nil
+	}
+	if _, ok := runsFields(n)[required[kind]]; !ok {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.Action.Runs.Line, fmt.Sprintf("Field '%s' is required in 'runs' of %s action", required[kind], kind)))
+	}
+	return validationErrors, nil
+}
+
+func validateActionRunsUnused(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Action.Runs == nil {
+		return validationErrors, nil
+	}
+	kind := getRuntimeKind(n.Action.Runs.Using)
+	if kind == "" {
+		return validationErrors, nil
+	}
+	fields := runsFields(n)
+	for _, field := range sortedKeys(fields) {
+		if !strings.Contains(fields[field], kind) {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOfFrom(n.Action.Runs.Line, field+":"), fmt.Sprintf("Field '%s' in 'runs' is used only by %s actions and not by %s ones", field, fields[field], kind)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateActionRunsFiles(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	r := n.Action.Runs
+	if r == nil || n.Action.DirName == "" {
+		return validationErrors, nil
+	}
+	files := map[string]string{}
+	switch getRuntimeKind(r.Using) {
+	case "node":
+		files["main"] = r.Main
+		files["pre"] = r.Pre
+		files["post"] = r.Post
+	case runsDocker:
+		if r.Image != "" && !strings.HasPrefix(r.Image, "docker://") {
+			files["image"] = r.Image
+		}
+	}
+	dir := filepath.Dir(n.Action.Path)
+	for _, field := range sortedKeys(files) {
+		if files[field] == "" {
+			continue
+		}
+		_, err := os.Stat(filepath.Join(dir, files[field]))
+		if err == nil {
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return validationErrors, err
+		}
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOfFrom(r.Line, field+":"), fmt.Sprintf("File '%s' in 'runs.%s' does not exist in the action directory", files[field], field)))
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,15 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestRunsRules(t *testing.T) {
+	testFixture(t, "runs", nil, []fixtureCase{
+		{"EA871", []string{"EA871:4", "EA871:0"}},
+		{"WA871", []string{"WA871:4"}},
+		{"EA872", []string{"EA872:4"}},
+		{"EA873", []string{"EA873:5", "EA873:6", "EA873:7"}},
+		{"EA874", []string{"EA874:5", "EA874:6"}},
+	})
+}
@@ -0,0 +1,147 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EW921", "Secret is printed in run script", validateStepSecretsPrinted, NodeWorkflowStep)
+	registerBuiltin("WW921", "Secret is used directly in run script", validateStepSecretsInRun, NodeWorkflowStep)
+	registerBuiltin("WW922", "Secret is set in workflow 'env' and is exposed to every step", validateWorkflowSecretsInEnv, NodeWorkflow)
+	registerBuiltin("WW923", "Secret is passed to a third-party action", validateStepSecretsInThirdPartyAction, NodeWorkflowStep)
+}
+
+// findSecrets returns names of secrets referenced in '${{ }}' expressions in s, in order of appearance.
+func findSecrets(s string) []string {
+	var names []string
+	reExpr := regexp.MustCompile(`\${{(.*?)}}`)
+	reSecret := regexp.MustCompile(`secrets\.([a-zA-Z0-9\-_]+)|secrets\[['"]([a-zA-Z0-9\-_]+)['"]\]`)
+	for _, expr := range reExpr.FindAllStringSubmatch(s, -1) {
+		for _, f := range reSecret.FindAllStringSubmatch(expr[1], -1) {
+			names = append(names, f[1]+f[2])
+		}
+	}
+	return names
+}
+
+// getEnvSecrets returns env variables available to the step that are set to a secret, along with the secret.
+func getEnvSecrets(n *Node) map[string]string {
+	envSecrets := map[string]string{}
+	for _, env := range []map[string]string{n.Workflow.Env, n.Job.Env, n.Step.Env} {
+		for k, v := range env {
+			secrets := findSecrets(v)
+			if len(secrets) > 0 {
+				envSecrets[k] = secrets[0]
+			} else {
+				delete(envSecrets, k)
+			}
+		}
+	}
+	return envSecrets
+}
+
+// isPrintingLine returns true when a line of the script writes to the output of the job.  Output piped to
+// another command, eg. 'docker login --password-stdin', and '::add-mask::' command do not print the secret.
+func isPrintingLine(line string) bool {
+	m, _ := regexp.MatchString(`^(echo|printf|Write-Host|Write-Output|print|cat[ ]+<<)\b`, strings.TrimSpace(line))
+	if !m || strings.Contains(line, "::add-mask::") {
+		return false
+	}
+	unquoted := regexp.MustCompile(`"(\\.|[^"\\])*"|'[^']*'`).ReplaceAllString(line, "")
+	if regexp.MustCompile(`(^|[^|])\|([^|]|$)`).MatchString(unquoted) {
+		return false
+	}
+	// Writing to files, eg. $GITHUB_OUTPUT, does not print anything.
+	return !regexp.MustCompile(`>>?[ ]*["']?[\$/a-zA-Z{]`).MatchString(line)
+}
+
+func validateStepSecretsPrinted(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Step.Run == "" {
+		return validationErrors, nil
+	}
+	envSecrets := getEnvSecrets(n)
+	for _, line := range strings.Split(n.Step.Run, "\n") {
+		if !isPrintingLine(line) {
+			continue
+		}
+		for _, secret := range findSecrets(line) {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(strings.TrimSpace(line)), fmt.Sprintf("Secret '%s' is printed in run script", secret)))
+		}
+		for _, envName := range sortedKeys(envSecrets) {
+			m, _ := regexp.MatchString(fmt.Sprintf(`\$(%s\b|{%s}|env:%s\b)|\${{[ ]*env\.%s[ ]*}}`, envName, envName, envName, envName), line)
+			if m {
+				validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(strings.TrimSpace(line)), fmt.Sprintf("Secret '%s' is printed in run script through env variable '%s'", envSecrets[envName], envName)))
+			}
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateStepSecretsInRun(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Step.Run == "" {
+		return validationErrors, nil
+	}
+	for _, line := range strings.Split(n.Step.Run, "\n") {
+		if isPrintingLine(line) {
+			continue
+		}
+		for _, secret := range findSecrets(line) {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(strings.TrimSpace(line)), fmt.Sprintf("Secret '%s' is used directly in run script - pass it to the step 'env' instead, so it is not pasted into the script and the command line", secret)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowSecretsInEnv(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, envName := range sortedKeys(n.Workflow.Env) {
+		for _, secret := range findSecrets(n.Workflow.Env[envName]) {
+			if secret == "GITHUB_TOKEN" {
+				continue
+			}
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(n.Workflow.Env[envName]), fmt.Sprintf("Secret '%s' is set in workflow env variable '%s' and is exposed to every step of every job - set it in the step 'env' instead", secret, envName)))
+		}
+	}
+	return validationErrors, nil
+}
+
+// isThirdPartyAction returns true when the external action is not from GitHub, and not from an owner that is
+// allowed to be unpinned in the configuration, which is considered trusted.
+func isThirdPartyAction(n *Node, repo string) bool {
+	owner := strings.ToLower(strings.Split(repo, "/")[0])
+	if owner == "actions" || owner == "github" {
+		return false
+	}
+	return !n.Config.IsUnpinnedAllowed(repo)
+}
+
+func validateStepSecretsInThirdPartyAction(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	repo, ref, ok := parseRemoteUses(n.Step.Uses)
+	if !ok || !isThirdPartyAction(n, repo) {
+		return validationErrors, nil
+	}
+	pinned, err := regexp.MatchString(`^[0-9a-f]{40}$`, ref)
+	if err != nil {
+		return validationErrors, err
+	}
+	for _, inputName := range sortedKeys(n.Step.With) {
+		for _, secret := range findSecrets(n.Step.With[inputName]) {
+			if secret == "GITHUB_TOKEN" {
+				continue
+			}
+			desc := fmt.Sprintf("Secret '%s' is passed to input '%s' of third-party action '%s'", secret, inputName, n.Step.Uses)
+			if !pinned {
+				desc += " that is not pinned to a commit SHA, so its code can change at any time"
+			}
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(n.Step.With[inputName]), desc))
+		}
+	}
+	return validationErrors, nil
+}
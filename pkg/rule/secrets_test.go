@@ -0,0 +1,38 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestIsPrintingLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`echo "${{ secrets.X }}"`, true},
+		{`  printf '%s' "$X"`, true},
+		{`Write-Host $env:X`, true},
+		{`echo "$X" >> "$GITHUB_ENV"`, false},
+		{`echo "$X" > /tmp/file`, false},
+		{`echo "$X" | docker login -u x --password-stdin`, false},
+		{`echo "$X"|base64 -d > key`, false},
+		{`echo "::add-mask::$X"`, false},
+		{`echo "a | b" "$X"`, true},
+		{`echo "$X" || true`, true},
+		{`curl -H "$X" https://example.com`, false},
+	}
+	for _, tt := range tests {
+		if got := isPrintingLine(tt.line); got != tt.want {
+			t.Errorf("isPrintingLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSecretsRules(t *testing.T) {
+	testFixture(t, "secrets", nil, []fixtureCase{
+		{"EW921", []string{"EW921:11", "EW921:15"}},
+		{"WW921", []string{"WW921:12", "WW921:13", "WW921:14", "WW921:16"}},
+		{"WW922", []string{"WW922:4"}},
+		{"WW923", []string{"WW923:21"}},
+	})
+}
@@ -0,0 +1,450 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
+)
+
+func init() {
+	registerBuiltin("EA851", "Run script has a syntax error", validateStepShellSyntax, NodeActionStep)
+	registerBuiltin("EW851", "Run script has a syntax error", validateStepShellSyntax, NodeWorkflowStep)
+	registerBuiltin("WA852", "Variable in run script is not quoted", validateStepShellUnquotedVars, NodeActionStep)
+	registerBuiltin("WW852", "Variable in run script is not quoted", validateStepShellUnquotedVars, NodeWorkflowStep)
+	registerBuiltin("WA853", "Run script calls 'cd' without error handling", validateStepShellCd, NodeActionStep)
+	registerBuiltin("WW853", "Run script calls 'cd' without error handling", validateStepShellCd, NodeWorkflowStep)
+	registerBuiltin("WA854", "Multi-line run script does not stop on errors", validateStepShellErrexit, NodeActionStep)
+	registerBuiltin("WW854", "Multi-line run script does not stop on errors", validateStepShellErrexit, NodeWorkflowStep)
+	registerBuiltin("WA855", "Variable in run script is not defined", validateStepShellUndefinedVars, NodeActionStep)
+	registerBuiltin("WW855", "Variable in run script is not defined", validateStepShellUndefinedVars, NodeWorkflowStep)
+	registerBuiltin("EA861", "Step with 'run' in composite action must have 'shell'", validateCompositeStepShell, NodeActionStep)
+	registerBuiltin("EA862", "Shell is invalid", validateStepShell, NodeActionStep)
+	registerBuiltin("EW862", "Shell is invalid", validateStepShell, NodeWorkflowStep)
+	registerBuiltin("EW863", "Default shell is invalid", validateDefaultsShell, NodeWorkflow, NodeWorkflowJob)
+	registerBuiltin("EA864", "Field 'shell' or 'working-directory' is used in a step without 'run'", validateStepRunOnlyFields, NodeActionStep)
+	registerBuiltin("EW864", "Field 'shell' or 'working-directory' is used in a step without 'run'", validateStepRunOnlyFields, NodeWorkflowStep)
+}
+
+// shellScript is a parsed run script of a step.  Errexit is the state of each of the top-level statements, ie.
+// whether the shell stops on errors at it.
+type shellScript struct {
+	File    *syntax.File
+	Errexit []bool
+	Err     error
+}
+
+// getStepShell returns shell of the step, taking job and workflow 'defaults' into account, or an empty string
+// when it is not set.
+func getStepShell(n *Node) string {
+	if n.Step.Shell != "" || n.Kind != NodeWorkflowStep {
+		return strings.TrimSpace(n.Step.Shell)
+	}
+	for _, d := range []*workflow.WorkflowDefaults{n.Job.Defaults, n.Workflow.Defaults} {
+		if d != nil && d.Run != nil && d.Run.Shell != "" {
+			return strings.TrimSpace(d.Run.Shell)
+		}
+	}
+	return ""
+}
+
+// supportedShells are the shells that can be used by name.  Other ones must be a command with '{0}', which is
+// replaced with path to the script.
+var supportedShells = map[string]bool{
+	"bash":       true,
+	"cmd":        true,
+	"powershell": true,
+	"pwsh":       true,
+	"python":     true,
+	"sh":         true,
+}
+
+// validateShellName returns description of the problem with shell, or an empty string when it is valid.
+func validateShellName(shell string) string {
+	shell = strings.TrimSpace(shell)
+	if shell == "" || supportedShells[shell] {
+		return ""
+	}
+	if !strings.Contains(shell, "{0}") {
+		return fmt.Sprintf("Shell '%s' is invalid - use one of bash, pwsh, python, sh, cmd, powershell or a command with '{0}'", shell)
+	}
+	return ""
+}
+
+func validateStepShell(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if desc := validateShellName(n.Step.Shell); desc != "" {
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf("shell:"), desc))
+	}
+	return validationErrors, nil
+}
+
+func validateDefaultsShell(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	d := n.Workflow.Defaults
+	if n.Kind == NodeWorkflowJob {
+		d = n.Job.Defaults
+	}
+	if d == nil || d.Run == nil {
+		return validationErrors, nil
+	}
+	if desc := validateShellName(d.Run.Shell); desc != "" {
+		validationErrors = append(validationErrors, n.FindingAtLine(d.Run.Line, "Default "+strings.ToLower(desc[:1])+desc[1:]))
+	}
+	return validationErrors, nil
+}
+
+func validateCompositeStepShell(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Step.Run != "" && n.Step.Shell == "" && n.Action.Runs != nil && n.Action.Runs.Using == "composite" {
+		validationErrors = append(validationErrors, n.Finding("Step with 'run' in composite action must have 'shell'"))
+	}
+	return validationErrors, nil
+}
+
+func validateStepRunOnlyFields(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Step.Run != "" {
+		return validationErrors, nil
+	}
+	if n.Step.Shell != "" {
+		validationErrors = append(validationErrors, n.Finding("Field 'shell' can be used only in a step with 'run'"))
+	}
+	if n.Step.WorkingDirectory != "" {
+		validationErrors = append(validationErrors, n.Finding("Field 'working-directory' can be used only in a step with 'run'"))
+	}
+	return validationErrors, nil
+}
+
+// isShellLinted returns true when the step script is run with bash or sh.  Steps without shell on Windows
+// runners use pwsh.
+func isShellLinted(n *Node) bool {
+	if n.Step.Run == "" {
+		return false
+	}
+	shell := getStepShell(n)
+	if shell == "" {
+		return n.Kind != NodeWorkflowStep || n.Job.RunsOn.GetOS() != "windows"
+	}
+	name := strings.Fields(shell)[0]
+	return name == "bash" || name == "sh"
+}
+
+// isShellErrexit returns true when the shell stops on errors by default.  Shells 'bash' and 'sh', and a step
+// without shell are run with '-e' flag by GitHub.
+func isShellErrexit(n *Node) bool {
+	shell := getStepShell(n)
+	if shell == "" || shell == "bash" || shell == "sh" {
+		return true
+	}
+	m, _ := regexp.MatchString(`(^|\s)-[a-zA-Z]*e|errexit`, shell)
+	return m
+}
+
+func parseStepScript(n *Node) *shellScript {
+	f, err := n.Step.ParseRun(strings.HasPrefix(getStepShell(n), "sh"))
+	if err != nil {
+		return &shellScript{Err: err}
+	}
+	s := &shellScript{File: f}
+	errexit := isShellErrexit(n)
+	for _, stmt := range f.Stmts {
+		if call, ok := stmt.Cmd.(*syntax.CallExpr); ok && len(call.Args) > 1 && call.Args[0].Lit() == "set" {
+			for _, a := range call.Args[1:] {
+				flag := a.Lit()
+				if shellSetErrexit.MatchString(flag) {
+					errexit = true
+				}
+				if shellUnsetErrexit.MatchString(flag) {
+					errexit = false
+				}
+			}
+			args := wordsLit(call.Args[1:])
+			if strings.Contains(args, "-o errexit") {
+				errexit = true
+			}
+			if strings.Contains(args, "+o errexit") {
+				errexit = false
+			}
+		}
+		s.Errexit = append(s.Errexit, errexit)
+	}
+	return s
+}
+
+func wordsLit(words []*syntax.Word) string {
+	var l []string
+	for _, w := range words {
+		l = append(l, w.Lit())
+	}
+	return strings.Join(l, " ")
+}
+
+// scriptLine returns line in the YAML file of a line in the script.
+func scriptLine(n *Node, line uint) int {
+	if n.Step.RunLine == 0 {
+		return n.Line()
+	}
+	return n.Step.RunLine + int(line) - 1
+}
+
+func validateStepShellSyntax(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isShellLinted(n) {
+		return validationErrors, nil
+	}
+	s := parseStepScript(n)
+	if s.Err == nil {
+		return validationErrors, nil
+	}
+	var parseErr syntax.ParseError
+	var langErr syntax.LangError
+	switch {
+	case errors.As(s.Err, &parseErr):
+		validationErrors = append(validationErrors, n.FindingAtLine(scriptLine(n, parseErr.Pos.Line()), fmt.Sprintf("Run script has a syntax error at line %d: %s", parseErr.Pos.Line(), parseErr.Text)))
+	case errors.As(s.Err, &langErr):
+		validationErrors = append(validationErrors, n.FindingAtLine(scriptLine(n, langErr.Pos.Line()), fmt.Sprintf("Run script uses %s that is not supported by the shell", langErr.Feature)))
+	default:
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Run script has a syntax error: %s", s.Err.Error())))
+	}
+	return validationErrors, nil
+}
+
+// isSafeUnquoted returns true for parameters that expand to a single word, eg. '$?' or '$#'.
+func isSafeUnquoted(p *syntax.ParamExp) bool {
+	switch p.Param.Value {
+	case "?", "#", "$", "!", "-":
+		return true
+	}
+	return p.Length
+}
+
+func validateStepShellUnquotedVars(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isShellLinted(n) {
+		return validationErrors, nil
+	}
+	s := parseStepScript(n)
+	if s.Err != nil {
+		return validationErrors, nil
+	}
+	syntax.Walk(s.File, func(node syntax.Node) bool {
+		call, ok := node.(*syntax.CallExpr)
+		if !ok {
+			return true
+		}
+		for _, w := range call.Args {
+			for _, part := range w.Parts {
+				p, ok := part.(*syntax.ParamExp)
+				if !ok || isSafeUnquoted(p) {
+					continue
+				}
+				validationErrors = append(validationErrors, n.FindingAtLine(scriptLine(n, p.Pos().Line()), fmt.Sprintf("Variable '%s' is not quoted in run script - use double quotes to prevent word splitting", p.Param.Value)))
+			}
+		}
+		return true
+	})
+	return validationErrors, nil
+}
+
+// getHandledCalls returns commands whose failure is handled, eg. with '||', '&&' or as a condition.
+func getHandledCalls(f *syntax.File) map[*syntax.CallExpr]bool {
+	handled := map[*syntax.CallExpr]bool{}
+	markStmts := func(stmts ...*syntax.Stmt) {
+		for _, stmt := range stmts {
+			if call, ok := stmt.Cmd.(*syntax.CallExpr); ok {
+				handled[call] = true
+			}
+		}
+	}
+	syntax.Walk(f, func(node syntax.Node) bool {
+		switch x := node.(type) {
+		case *syntax.BinaryCmd:
+			if x.Op == syntax.AndStmt || x.Op == syntax.OrStmt {
+				markStmts(x.X)
+			}
+		case *syntax.IfClause:
+			markStmts(x.Cond...)
+		case *syntax.WhileClause:
+			markStmts(x.Cond...)
+		}
+		return true
+	})
+	return handled
+}
+
+func validateStepShellCd(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isShellLinted(n) {
+		return validationErrors, nil
+	}
+	s := parseStepScript(n)
+	if s.Err != nil {
+		return validationErrors, nil
+	}
+	handled := getHandledCalls(s.File)
+	for i, stmt := range s.File.Stmts {
+		if s.Errexit[i] {
+			continue
+		}
+		syntax.Walk(stmt, func(node syntax.Node) bool {
+			call, ok := node.(*syntax.CallExpr)
+			if ok && len(call.Args) > 0 && call.Args[0].Lit() == "cd" && !handled[call] {
+				validationErrors = append(validationErrors, n.FindingAtLine(scriptLine(n, call.Pos().Line()), "Run script calls 'cd' without error handling and 'set -e' - use 'cd dir || exit 1'"))
+			}
+			return true
+		})
+	}
+	return validationErrors, nil
+}
+
+func validateStepShellErrexit(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isShellLinted(n) || isShellErrexit(n) {
+		return validationErrors, nil
+	}
+	s := parseStepScript(n)
+	if s.Err != nil || len(s.File.Stmts) < 2 {
+		return validationErrors, nil
+	}
+	if !s.Errexit[0] {
+		validationErrors = append(validationErrors, n.FindingAtLine(scriptLine(n, s.File.Stmts[0].Pos().Line()), "Multi-line run script does not stop on errors - add 'set -e' at the beginning or use shell with '-e' flag"))
+	}
+	return validationErrors, nil
+}
+
+var (
+	shellSetErrexit   = regexp.MustCompile(`^-[a-zA-Z]*e`)
+	shellUnsetErrexit = regexp.MustCompile(`^\+[a-zA-Z]*e`)
+	shellVarName      = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	shellLowercase    = regexp.MustCompile(`[a-z]`)
+)
+
+// knownShellVars are variables set by the shell or the runner.
+var knownShellVars = map[string]bool{
+	"BASH_SOURCE": true, "BASHPID": true, "CI": true, "HOME": true, "HOSTNAME": true, "IFS": true, "LANG": true,
+	"LINENO": true, "OLDPWD": true, "OPTARG": true, "OPTIND": true, "PATH": true, "PIPESTATUS": true, "PPID": true,
+	"PWD": true, "RANDOM": true, "REPLY": true, "SECONDS": true, "SHELL": true, "TMPDIR": true, "UID": true,
+	"USER": true,
+}
+
+// getDefinedShellVars returns variables that are set in the script, ie. assigned, also in arithmetic, declared,
+// read, or used as a loop variable.
+func getDefinedShellVars(f *syntax.File) map[string]bool {
+	defined := map[string]bool{}
+	syntax.Walk(f, func(node syntax.Node) bool {
+		switch x := node.(type) {
+		case *syntax.Assign:
+			if x.Name != nil {
+				defined[x.Name.Value] = true
+			}
+		case *syntax.WordIter:
+			defined[x.Name.Value] = true
+		case *syntax.BinaryArithm:
+			// Assignment in '(( ))', '$(( ))' or a C-style 'for' loop, eg. 'i=0' or 'n+=2'.
+			switch x.Op {
+			case syntax.Assgn, syntax.AddAssgn, syntax.SubAssgn, syntax.MulAssgn, syntax.QuoAssgn, syntax.RemAssgn,
+				syntax.AndAssgn, syntax.OrAssgn, syntax.XorAssgn, syntax.ShlAssgn, syntax.ShrAssgn:
+				defineArithmVar(defined, x.X)
+			}
+		case *syntax.UnaryArithm:
+			if x.Op == syntax.Inc || x.Op == syntax.Dec {
+				defineArithmVar(defined, x.X)
+			}
+		case *syntax.CallExpr:
+			if len(x.Args) == 0 {
+				break
+			}
+			switch x.Args[0].Lit() {
+			case "read", "mapfile", "readarray", "getopts", "printf", "export", "declare", "local":
+				for _, a := range x.Args[1:] {
+					name := strings.SplitN(a.Lit(), "=", 2)[0]
+					if shellVarName.MatchString(name) {
+						defined[name] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	return defined
+}
+
+// defineArithmVar adds the variable that is assigned or incremented in an arithmetic expression.
+func defineArithmVar(defined map[string]bool, x syntax.ArithmExpr) {
+	w, ok := x.(*syntax.Word)
+	if !ok {
+		return
+	}
+	if name := w.Lit(); name != "" {
+		defined[name] = true
+	}
+}
+
+// getStepAvailableEnv returns env variables set for the step in the workflow or the action.
+func getStepAvailableEnv(n *Node) map[string]bool {
+	available := map[string]bool{}
+	envs := []map[string]string{n.Step.Env}
+	if n.Kind == NodeWorkflowStep {
+		envs = append(envs, n.Workflow.Env, n.Job.Env)
+		for env, i := range getJobEnvWrites(n) {
+			if i < n.StepIndex {
+				available[env] = true
+			}
+		}
+	}
+	for _, env := range envs {
+		for k := range env {
+			available[k] = true
+		}
+	}
+	return available
+}
+
+func validateStepShellUndefinedVars(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isShellLinted(n) {
+		return validationErrors, nil
+	}
+	s := parseStepScript(n)
+	if s.Err != nil {
+		return validationErrors, nil
+	}
+	defined := getDefinedShellVars(s.File)
+	available := getStepAvailableEnv(n)
+	reported := map[string]bool{}
+	var names []string
+	lines := map[string]uint{}
+	syntax.Walk(s.File, func(node syntax.Node) bool {
+		p, ok := node.(*syntax.ParamExp)
+		if !ok || p.Param == nil || p.Excl {
+			return true
+		}
+		name := p.Param.Value
+		if p.Exp != nil && p.Exp.Op != syntax.RemSmallPrefix && p.Exp.Op != syntax.RemLargePrefix && p.Exp.Op != syntax.RemSmallSuffix && p.Exp.Op != syntax.RemLargeSuffix {
+			// Default value or an error is given for an unset variable.
+			return true
+		}
+		if reported[name] || defined[name] || available[name] || knownShellVars[name] {
+			return true
+		}
+		if !shellVarName.MatchString(name) || !shellLowercase.MatchString(name) {
+			return true
+		}
+		reported[name] = true
+		names = append(names, name)
+		lines[name] = p.Pos().Line()
+		return true
+	})
+	sort.SliceStable(names, func(i, j int) bool { return lines[names[i]] < lines[names[j]] })
+	for _, name := range names {
+		validationErrors = append(validationErrors, n.FindingAtLine(scriptLine(n, lines[name]), fmt.Sprintf("Variable '%s' is used in run script but is never set", name)))
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,46 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+)
+
+func TestShellRules(t *testing.T) {
+	testFixture(t, "shell", nil, []fixtureCase{
+		{"EW851", []string{"EW851:10", "EW851:31"}},
+		{"WW852", []string{"WW852:13"}},
+		{"WW853", []string{"WW853:17"}},
+		{"WW854", []string{"WW854:17"}},
+		{"WW855", []string{"WW855:28"}},
+	})
+}
+
+func TestGetDefinedShellVars(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{`a=1; export b=2; read -r c d`, []string{"a", "b", "c", "d"}},
+		{`for e in x y; do :; done`, []string{"e"}},
+		{`for ((i=0; i<3; i++)); do :; done`, []string{"i"}},
+		{`(( j++ )); (( --k ))`, []string{"j", "k"}},
+		{`echo $(( l += 2 )); let m=1 n*=2`, []string{"l", "m", "n"}},
+		{`echo "$o" $(( p + 1 ))`, []string{}},
+	}
+	for _, tt := range tests {
+		f, err := action.ParseShellScript(tt.script, false)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %s", tt.script, err)
+		}
+		got := getDefinedShellVars(f)
+		for _, name := range tt.want {
+			if !got[name] {
+				t.Errorf("%q: '%s' is not defined", tt.script, name)
+			}
+		}
+		if len(got) > len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.script, got, tt.want)
+		}
+	}
+}
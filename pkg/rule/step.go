@@ -0,0 +1,294 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("NA701", "Env variable name should follow the naming convention, SCREAMING_SNAKE by default", validateStepEnv("NA701"), NodeActionStep)
+	registerBuiltin("NW701", "Env variable name should follow the naming convention, SCREAMING_SNAKE by default", validateStepEnv("NW701"), NodeWorkflowStep)
+	registerBuiltin("EA801", "Path to external action is invalid", validateStepExternalActionPath, NodeActionStep)
+	registerBuiltin("EW801", "Path to external action is invalid", validateStepExternalActionPath, NodeWorkflowStep)
+	registerBuiltin("EA802", "Path to local action is invalid", validateStepLocalActionPath, NodeActionStep)
+	registerBuiltin("EW802", "Path to local action is invalid", validateStepLocalActionPath, NodeWorkflowStep)
+	registerBuiltin("EA803", "Call to non-existing local action", validateStepLocalActionExists, NodeActionStep)
+	registerBuiltin("EW803", "Call to non-existing local action", validateStepLocalActionExists, NodeWorkflowStep)
+	registerBuiltin("EA804", "Required input missing for local action", validateStepLocalActionRequiredInputs, NodeActionStep)
+	registerBuiltin("EW804", "Required input missing for local action", validateStepLocalActionRequiredInputs, NodeWorkflowStep)
+	registerBuiltin("EA805", "Input does not exist in local action", validateStepLocalActionInputs, NodeActionStep)
+	registerBuiltin("EW805", "Input does not exist in local action", validateStepLocalActionInputs, NodeWorkflowStep)
+	registerBuiltin("EA806", "Required input missing for external action", validateStepExternalActionRequiredInputs, NodeActionStep)
+	registerBuiltin("EW806", "Required input missing for external action", validateStepExternalActionRequiredInputs, NodeWorkflowStep)
+	registerBuiltin("EA807", "Input does not exist in external action", validateStepExternalActionInputs, NodeActionStep)
+	registerBuiltin("EW807", "Input does not exist in external action", validateStepExternalActionInputs, NodeWorkflowStep)
+	registerBuiltin("EA808", "Call to non-existing external action", validateStepExternalActionExists, NodeActionStep)
+	registerBuiltin("EW808", "Call to non-existing external action", validateStepExternalActionExists, NodeWorkflowStep)
+	registerBuiltin("EA809", "Called step does not exist", validateActionStepCalledSteps, NodeActionStep)
+	registerBuiltin("EA811", "Called step output does not exist", validateActionStepCalledStepOutputs, NodeActionStep)
+	registerBuiltin("EW811", "Called step output does not exist", validateWorkflowStepCalledStepOutputs, NodeWorkflowStep)
+	registerBuiltin("WW101", "Called env var not found in global, job or step 'env' block", validateWorkflowStepCalledEnv, NodeWorkflowStep)
+}
+
+func validateStepEnv(code string) func(n *Node) ([]*finding.Finding, error) {
+	return func(n *Node) ([]*finding.Finding, error) {
+		return validateEnvNames(n, code, n.Step.Env)
+	}
+}
+
+func isLocalActionUses(uses string) bool {
+	return strings.HasPrefix(uses, "./.github/")
+}
+
+func isExternalActionUses(uses string) bool {
+	if uses == "" || isLocalActionUses(uses) {
+		return false
+	}
+	m, _ := regexp.MatchString(`[a-zA-Z0-9\-\_]+\/[a-zA-Z0-9\-\_]+(\/[a-zA-Z0-9\-\_]){0,1}@[a-zA-Z0-9\.\-\_]+`, uses)
+	return m
+}
+
+func validateStepExternalActionPath(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	uses := n.Step.Uses
+	if uses != "" && !isLocalActionUses(uses) && !isExternalActionUses(uses) {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Path to external action '%s' is invalid", uses)))
+	}
+	return validationErrors, nil
+}
+
+func validateStepLocalActionPath(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	uses := n.Step.Uses
+	if !isLocalActionUses(uses) {
+		return validationErrors, nil
+	}
+	m, err := regexp.MatchString(`^\.\/\.github\/actions\/([a-z0-9\-]+|[a-z0-9\-]+\/[a-z0-9\-]+)$`, uses)
+	if err != nil {
+		return validationErrors, err
+	}
+	if !m {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Path to local action '%s' is invalid", uses)))
+	}
+	return validationErrors, nil
+}
+
+// getLocalAction returns the local action called by the step, or nil when step does not call an existing one.
+func getLocalAction(n *Node) *action.Action {
+	if !isLocalActionUses(n.Step.Uses) {
+		return nil
+	}
+	return n.DotGithub.GetAction(strings.Replace(n.Step.Uses, "./.github/actions/", "", -1))
+}
+
+// getExternalAction returns the external action called by the step.  Action is downloaded if needed.
+func getExternalAction(n *Node) (*action.Action, error) {
+	if !isExternalActionUses(n.Step.Uses) {
+		return nil, nil
+	}
+	err := n.DotGithub.DownloadExternalAction(n.Step.Uses)
+	if err != nil {
+		return nil, err
+	}
+	return n.DotGithub.GetExternalAction(n.Step.Uses), nil
+}
+
+func validateStepLocalActionExists(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if isLocalActionUses(n.Step.Uses) && getLocalAction(n) == nil {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Call to non-existing local action '%s'", n.Step.Uses)))
+	}
+	return validationErrors, nil
+}
+
+func validateStepLocalActionRequiredInputs(n *Node) ([]*finding.Finding, error) {
+	return validateStepRequiredInputs(n, getLocalAction(n), "local")
+}
+
+func validateStepLocalActionInputs(n *Node) ([]*finding.Finding, error) {
+	return validateStepInputs(n, getLocalAction(n), "local")
+}
+
+func validateStepExternalActionExists(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isExternalActionUses(n.Step.Uses) {
+		return validationErrors, nil
+	}
+	a, err := getExternalAction(n)
+	if err != nil {
+		return validationErrors, err
+	}
+	if a == nil {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Call to non-existing external action '%s'", n.Step.Uses)))
+	}
+	return validationErrors, nil
+}
+
+func validateStepExternalActionRequiredInputs(n *Node) ([]*finding.Finding, error) {
+	a, err := getExternalAction(n)
+	if err != nil {
+		return nil, err
+	}
+	return validateStepRequiredInputs(n, a, "external")
+}
+
+func validateStepExternalActionInputs(n *Node) ([]*finding.Finding, error) {
+	a, err := getExternalAction(n)
+	if err != nil {
+		return nil, err
+	}
+	return validateStepInputs(n, a, "external")
+}
+
+func validateStepRequiredInputs(n *Node, a *action.Action, actionType string) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if a == nil {
+		return validationErrors, nil
+	}
+	for _, inputName := range sortedKeys(a.Inputs) {
+		if a.Inputs[inputName] != nil && a.Inputs[inputName].Required {
+			if n.Step.With == nil || n.Step.With[inputName] == "" {
+				validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Required input '%s' missing for %s action '%s'", inputName, actionType, n.Step.Uses)))
+			}
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateStepInputs(n *Node, a *action.Action, actionType string) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if a == nil {
+		return validationErrors, nil
+	}
+	for _, usedInput := range sortedKeys(n.Step.With) {
+		if a.Inputs == nil || a.Inputs[usedInput] == nil {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Input '%s' does not exist in %s action '%s'", usedInput, actionType, n.Step.Uses)))
+		}
+	}
+	return validationErrors, nil
+}
+
+// findCalledStepOutputs returns pairs of step id and output called in the 'run' and 'env' of the step.
+func findCalledStepOutputs(s *action.ActionStep) [][2]string {
+	var called [][2]string
+	if s.Run == "" {
+		return called
+	}
+	runAndEnvsStr := s.Run
+	for _, envName := range sortedKeys(s.Env) {
+		runAndEnvsStr = runAndEnvsStr + " " + s.Env[envName]
+	}
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*steps\\.([a-zA-Z0-9\\-_]+)\\.outputs\\.([a-zA-Z0-9\\-_]+)[ ]*}}"))
+	found := re.FindAllStringSubmatch(runAndEnvsStr, -1)
+	for _, f := range found {
+		called = append(called, [2]string{f[1], f[2]})
+	}
+	return called
+}
+
+func validateActionStepCalledSteps(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, called := range findCalledStepOutputs(n.Step) {
+		if n.Action.Runs == nil || n.Action.Runs.IsStepOutputExist(called[0], called[1], n.DotGithub) == -1 {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called step with id '%s' does not exist", called[0])))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateActionStepCalledStepOutputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, called := range findCalledStepOutputs(n.Step) {
+		if n.Action.Runs != nil && n.Action.Runs.IsStepOutputExist(called[0], called[1], n.DotGithub) == -2 {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called step with id '%s' output '%s' does not exist", called[0], called[1])))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowStepCalledStepOutputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, called := range findCalledStepOutputs(n.Step) {
+		if !n.DotGithub.IsWorkflowJobStepOutputExist(n.Workflow.FileName, n.JobName, called[0], called[1]) {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called step with id '%s' output '%s' does not exist", called[0], called[1])))
+		}
+	}
+	return validationErrors, nil
+}
+
+// getStepEnvWrites returns env variables written to $GITHUB_ENV by the step, including the ones written by steps
+// of the local composite action that it calls.
+func getStepEnvWrites(n *Node, s *action.ActionStep, visited map[string]bool) []string {
+	envs := s.GetRunEnvs()
+	if !isLocalActionUses(s.Uses) {
+		return envs
+	}
+	name := strings.Replace(s.Uses, "./.github/actions/", "", -1)
+	a := n.DotGithub.GetAction(name)
+	if a == nil || a.Runs == nil || visited[name] {
+		return envs
+	}
+	visited[name] = true
+	for _, as := range a.Runs.Steps {
+		if as != nil {
+			envs = append(envs, getStepEnvWrites(n, as, visited)...)
+		}
+	}
+	return envs
+}
+
+// getJobEnvWrites returns env variables written to $GITHUB_ENV by steps of the job, with index of the first
+// step writing each of them.
+func getJobEnvWrites(n *Node) map[string]int {
+	written := map[string]int{}
+	for i, s := range n.Job.Steps {
+		if s == nil {
+			continue
+		}
+		for _, env := range getStepEnvWrites(n, s, map[string]bool{}) {
+			if _, ok := written[env]; !ok {
+				written[env] = i
+			}
+		}
+	}
+	return written
+}
+
+func validateWorkflowStepCalledEnv(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Step.Run == "" {
+		return validationErrors, nil
+	}
+	written := getJobEnvWrites(n)
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*env\\.([a-zA-Z0-9\\-_]+)[ ]*}}"))
+	found := re.FindAllStringSubmatch(n.Step.Run, -1)
+	for _, f := range found {
+		if strings.HasPrefix(f[1], "GITHUB_") || strings.HasPrefix(f[1], "RUNNER_") || f[1] == "CI" {
+			continue
+		}
+		if n.Step.Env != nil && n.Step.Env[f[1]] != "" {
+			continue
+		}
+		if n.DotGithub.IsEnvExistInWorkflowOrItsJob(n.Workflow.FileName, n.JobName, f[1]) {
+			continue
+		}
+		i, ok := written[f[1]]
+		if ok && i < n.StepIndex {
+			continue
+		}
+		if ok && i == n.StepIndex {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called env var '%s' is written to $GITHUB_ENV by this step and is available only in the next steps - check it", f[1])))
+			continue
+		}
+		if ok {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called env var '%s' is written to $GITHUB_ENV only in a later step %d - check it", f[1], i)))
+			continue
+		}
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Called env var '%s' not found in global, job or step 'env' block - check it", f[1])))
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,24 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkflowStepCalledEnv(t *testing.T) {
+	findings := runFixture(t, "step", nil, "WW101")
+	assertCodeLines(t, findings, []string{"WW101:9", "WW101:10", "WW101:11"})
+	if len(findings) != 3 {
+		return
+	}
+	want := []string{
+		"'MISSING' not found",
+		"'LATER' is written to $GITHUB_ENV only in a later step 3",
+		"'SAME' is written to $GITHUB_ENV by this step",
+	}
+	for i, w := range want {
+		if !strings.Contains(findings[i].Description, w) {
+			t.Errorf("finding %d: got %q, want it to contain %q", i, findings[i].Description, w)
+		}
+	}
+}
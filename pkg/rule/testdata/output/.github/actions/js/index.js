@@ -0,0 +1 @@
+require('@actions/core').setOutput('out', 1);
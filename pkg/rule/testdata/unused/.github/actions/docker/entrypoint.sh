@@ -0,0 +1,2 @@
+#!/bin/sh
+echo "ok=1" >> "$GITHUB_OUTPUT"
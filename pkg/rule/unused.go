@@ -0,0 +1,300 @@
+package rule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("WA303", "Action input is not used", validateActionInputUsed, NodeActionInput)
+	registerBuiltin("WW303", "Workflow input is not used", validateWorkflowInputUsed, NodeWorkflowInput)
+	registerBuiltin("WA503", "Action output is never set", validateActionOutputSet, NodeActionOutput)
+	registerBuiltin("WW503", "Workflow output is not used by any of its callers", validateWorkflowOutputsUsed, NodeWorkflow)
+	registerBuiltin("WA703", "Env variable is not used", validateEnvUsed, NodeActionStep)
+	registerBuiltin("WW703", "Env variable is not used", validateEnvUsed, NodeWorkflow, NodeWorkflowJob, NodeWorkflowStep)
+}
+
+// nameEnd matches end of a name, which can contain hyphens.
+const nameEnd = `([^a-zA-Z0-9_\-]|$)`
+
+func isInputUsed(raw []byte, name string) bool {
+	q := regexp.QuoteMeta(name)
+	re := regexp.MustCompile(fmt.Sprintf(`inputs\.%s%s|inputs\[['"]%s['"]\]`, q, nameEnd, q))
+	return re.Match(raw)
+}
+
+func validateActionInputUsed(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	// Other actions get inputs as INPUT_* env variables in their code.
+	if !isCompositeAction(n.Action) {
+		return validationErrors, nil
+	}
+	if !isInputUsed(n.Raw(), n.Name) {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Action input '%s' is not used", n.Name)))
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowInputUsed(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if !isInputUsed(n.Raw(), n.Name) {
+		validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Workflow input '%s' is not used", n.Name)))
+	}
+	return validationErrors, nil
+}
+
+// getActionCodeFiles returns paths of files with code of a JavaScript or a Docker action, which are 'main',
+// 'pre' and 'post' scripts, or files next to the Dockerfile.  Nil is returned for other actions.
+func getActionCodeFiles(a *action.Action) ([]string, error) {
+	r := a.Runs
+	if r == nil || a.DirName == "" {
+		return nil, nil
+	}
+	dir := filepath.Dir(a.Path)
+	var files []string
+	switch getRuntimeKind(r.Using) {
+	case "node":
+		for _, f := range []string{r.Main, r.Pre, r.Post} {
+			if f != "" {
+				files = append(files, filepath.Join(dir, f))
+			}
+		}
+	case runsDocker:
+		if r.Image == "" || strings.HasPrefix(r.Image, "docker://") {
+			return nil, nil
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && e.Name() != filepath.Base(a.Path) {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// isOutputSetIn returns true when the code mentions the output, either as a string like in
+// 'core.setOutput("name", value)' or as 'name=' written to $GITHUB_OUTPUT.
+func isOutputSetIn(name string, code []byte) bool {
+	q := regexp.QuoteMeta(name)
+	re := regexp.MustCompile(fmt.Sprintf("(?m)['\"`]%s['\"`]|(^|[^a-zA-Z0-9_\\-])%s(=|<<)|::set-output name=%s::", q, q, q))
+	return re.Match(code)
+}
+
+func validateActionOutputSet(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	// Outputs of composite actions are values referring to step outputs, which are checked by EA511 and EA513.
+	files, err := getActionCodeFiles(n.Action)
+	if err != nil {
+		return validationErrors, err
+	}
+	if len(files) == 0 {
+		return validationErrors, nil
+	}
+	for _, f := range files {
+		code, err := os.ReadFile(f)
+		if os.IsNotExist(err) {
+			// Missing files are reported by EA874, and the output may be set in them.
+			return validationErrors, nil
+		}
+		if err != nil {
+			return validationErrors, err
+		}
+		if isOutputSetIn(n.Name, code) {
+			return validationErrors, nil
+		}
+	}
+	validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Action output '%s' is never set in the action code", n.Name)))
+	return validationErrors, nil
+}
+
+func validateWorkflowOutputsUsed(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	w := n.Workflow
+	if w.On == nil || w.On.WorkflowCall == nil || len(w.On.WorkflowCall.Outputs) == 0 {
+		return validationErrors, nil
+	}
+	uses := "./.github/workflows/" + w.FileName
+	callers := 0
+	used := map[string]bool{}
+	for _, name := range sortedKeys(n.DotGithub.Workflows) {
+		caller := n.DotGithub.Workflows[name]
+		if caller == nil {
+			continue
+		}
+		for _, jobName := range sortedKeys(caller.Jobs) {
+			if caller.Jobs[jobName] == nil || caller.Jobs[jobName].Uses != uses {
+				continue
+			}
+			callers++
+			for outputName := range w.On.WorkflowCall.Outputs {
+				re := regexp.MustCompile(fmt.Sprintf(`needs\.%s\.outputs\.%s%s`, regexp.QuoteMeta(jobName), regexp.QuoteMeta(outputName), nameEnd))
+				if re.Match(caller.Raw) {
+					used[outputName] = true
+				}
+			}
+		}
+	}
+	if callers == 0 {
+		return validationErrors, nil
+	}
+	for _, outputName := range sortedKeys(w.On.WorkflowCall.Outputs) {
+		o := w.On.WorkflowCall.Outputs[outputName]
+		if o == nil || used[outputName] {
+			continue
+		}
+		validationErrors = append(validationErrors, n.FindingAtLine(o.Line, fmt.Sprintf("Workflow output '%s' is not used by any of %d jobs calling the workflow", outputName, callers)))
+	}
+	return validationErrors, nil
+}
+
+// isEnvUsedIn returns true when any of the texts reads the env variable, either in an expression or in a script.
+func isEnvUsedIn(name string, texts []string) bool {
+	q := regexp.QuoteMeta(name)
+	re := regexp.MustCompile(fmt.Sprintf(`env\.%s\b|env\[['"]%s['"]\]|\$%s\b|\$\{[!#]?%s\b|\$env:%s\b|%%%s%%|environ(\.get\(|\[)['"]%s['"]|getenv\(['"]%s['"]`, q, q, q, q, q, q, q, q))
+	for _, t := range texts {
+		if re.MatchString(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// envReader is a step that can read env variables of a scope, unless they are overridden by one of the env
+// blocks in Hiding, eg. in its job or its own 'env'.
+type envReader struct {
+	Step   *action.ActionStep
+	Hiding []map[string]string
+}
+
+// canRead returns true when the variable from the scope is visible to the step.
+func (r envReader) canRead(name string) bool {
+	for _, env := range r.Hiding {
+		if _, ok := env[name]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// getEnvScope returns env of the node and steps that can read it.
+func getEnvScope(n *Node) (map[string]string, []envReader) {
+	switch n.Kind {
+	case NodeWorkflow:
+		var readers []envReader
+		for _, jobName := range sortedKeys(n.Workflow.Jobs) {
+			j := n.Workflow.Jobs[jobName]
+			if j == nil {
+				continue
+			}
+			for _, s := range j.Steps {
+				if s != nil {
+					readers = append(readers, envReader{Step: s, Hiding: []map[string]string{j.Env, s.Env}})
+				}
+			}
+		}
+		return n.Workflow.Env, readers
+	case NodeWorkflowJob:
+		var readers []envReader
+		for _, s := range n.Job.Steps {
+			if s != nil {
+				readers = append(readers, envReader{Step: s, Hiding: []map[string]string{s.Env}})
+			}
+		}
+		return n.Job.Env, readers
+	}
+	return n.Step.Env, []envReader{{Step: n.Step}}
+}
+
+// getStepTexts returns parts of the step that can read env variables.  False is returned when step calls an
+// action that is not composite or cannot be found, as it can read any env variable in its code.
+func getStepTexts(n *Node, s *action.ActionStep, visited map[string]bool) ([]string, bool) {
+	texts := []string{s.Run, s.If}
+	for _, k := range sortedKeys(s.With) {
+		texts = append(texts, s.With[k])
+	}
+	for _, k := range sortedKeys(s.Env) {
+		texts = append(texts, s.Env[k])
+	}
+	if s.Uses == "" {
+		return texts, true
+	}
+	if !isLocalActionUses(s.Uses) {
+		return texts, false
+	}
+	name := strings.Replace(s.Uses, "./.github/actions/", "", -1)
+	a := n.DotGithub.GetAction(name)
+	if a == nil || !isCompositeAction(a) {
+		return texts, false
+	}
+	if visited[name] {
+		return texts, true
+	}
+	visited[name] = true
+	readAll := false
+	for _, as := range a.Runs.Steps {
+		if as == nil {
+			continue
+		}
+		t, ok := getStepTexts(n, as, visited)
+		texts = append(texts, t...)
+		readAll = readAll || !ok
+	}
+	return texts, !readAll
+}
+
+func validateEnvUsed(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	env, readers := getEnvScope(n)
+	if len(env) == 0 {
+		return validationErrors, nil
+	}
+	var texts []string
+	var opaque []envReader
+	for _, r := range readers {
+		t, ok := getStepTexts(n, r.Step, map[string]bool{})
+		texts = append(texts, t...)
+		if !ok {
+			opaque = append(opaque, r)
+		}
+	}
+	switch n.Kind {
+	case NodeWorkflow:
+		// Workflow env variables can be read anywhere in the file, eg. in 'if' conditions or env of the jobs.
+		texts = append(texts, string(n.Raw()))
+	case NodeWorkflowJob:
+		// Job env variables are read only by the job itself, and a job calling a workflow cannot have env.
+		texts = append(texts, n.Job.If)
+	}
+	for _, name := range sortedKeys(env) {
+		// Variables like ACTIONS_STEP_DEBUG are read by the runner itself.
+		if strings.HasPrefix(name, "ACTIONS_") || strings.HasPrefix(name, "RUNNER_") {
+			continue
+		}
+		if isEnvUsedIn(name, texts) || isEnvReadByAny(name, opaque) {
+			continue
+		}
+		validationErrors = append(validationErrors, n.FindingAtLine(n.LineOf(name+":"), fmt.Sprintf("Env variable '%s' is not used", name)))
+	}
+	return validationErrors, nil
+}
+
+// isEnvReadByAny returns true when any of the steps calling an external action, which may read any variable
+// in its code, can see the variable.
+func isEnvReadByAny(name string, readers []envReader) bool {
+	for _, r := range readers {
+		if r.canRead(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package rule
+
+import (
+	"testing"
+)
+
+func TestUnusedRules(t *testing.T) {
+	testFixture(t, "unused", nil, []fixtureCase{
+		{"WA503", []string{"WA503:7", "WA503:7"}},
+		{"WW703", []string{"WW703:6", "WW703:19", "WW703:23", "WW703:34"}},
+	})
+}
+
+func TestIsOutputSetIn(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{`core.setOutput("result", x)`, true},
+		{"core.setOutput(`result`, x)", true},
+		{`echo "result=$x" >> "$GITHUB_OUTPUT"`, true},
+		{"echo 'result<<EOF' >> $GITHUB_OUTPUT", true},
+		{`echo "::set-output name=result::$x"`, true},
+		{`core.setOutput("result-2", x)`, false},
+		{`echo "my_result=$x" >> "$GITHUB_OUTPUT"`, false},
+		{`const result = 1`, false},
+	}
+	for _, tt := range tests {
+		if got := isOutputSetIn("result", []byte(tt.code)); got != tt.want {
+			t.Errorf("isOutputSetIn(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package rule
+
+import (
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+// Run visits all the nodes of the .github directory with every registered rule.  When enabled is not empty, only
+// rules with codes from the list are run.  Findings are sorted by file, position and code.
+func (r *Registry) Run(d *dotgithub.DotGithub, cfg *config.Config, enabled []string) ([]*finding.Finding, error) {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	var rules []Rule
+	for _, rule := range r.Rules() {
+		if isEnabled(rule.Code(), enabled) {
+			rules = append(rules, rule)
+		}
+	}
+
+	var validationErrors []*finding.Finding
+	visit := func(n *Node) error {
+		n.Config = cfg
+		for _, rule := range rules {
+			verrs, err := rule.Visit(n)
+			if err != nil {
+				return err
+			}
+			for _, verr := range verrs {
+				verr.Code = rule.Code()
+				verr.Severity = rule.Severity()
+				validationErrors = append(validationErrors, verr)
+			}
+		}
+		return nil
+	}
+
+	err := walkActions(d, visit)
+	if err != nil {
+		return validationErrors, err
+	}
+	err = walkWorkflows(d, visit)
+	if err != nil {
+		return validationErrors, err
+	}
+
+	err = finding.Sort(validationErrors, finding.SortByFile)
+	return validationErrors, err
+}
+
+func isEnabled(code string, enabled []string) bool {
+	if len(enabled) == 0 {
+		return true
+	}
+	for _, e := range enabled {
+		if e == code {
+			return true
+		}
+	}
+	return false
+}
+
+func walkActions(d *dotgithub.DotGithub, visit func(n *Node) error) error {
+	for _, actionName := range sortedKeys(d.Actions) {
+		a := d.Actions[actionName]
+		err := visit(&Node{Kind: NodeAction, DotGithub: d, Action: a})
+		if err != nil {
+			return err
+		}
+		for _, inputName := range sortedKeys(a.Inputs) {
+			if a.Inputs[inputName] == nil {
+				continue
+			}
+			err = visit(&Node{Kind: NodeActionInput, DotGithub: d, Action: a, ActionInput: a.Inputs[inputName], Name: inputName})
+			if err != nil {
+				return err
+			}
+		}
+		for _, outputName := range sortedKeys(a.Outputs) {
+			if a.Outputs[outputName] == nil {
+				continue
+			}
+			err = visit(&Node{Kind: NodeActionOutput, DotGithub: d, Action: a, ActionOutput: a.Outputs[outputName], Name: outputName})
+			if err != nil {
+				return err
+			}
+		}
+		if a.Runs == nil {
+			continue
+		}
+		for i, s := range a.Runs.Steps {
+			if s == nil {
+				continue
+			}
+			err = visit(&Node{Kind: NodeActionStep, DotGithub: d, Action: a, Step: s, StepIndex: i})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func walkWorkflows(d *dotgithub.DotGithub, visit func(n *Node) error) error {
+	for _, workflowName := range sortedKeys(d.Workflows) {
+		w := d.Workflows[workflowName]
+		err := visit(&Node{Kind: NodeWorkflow, DotGithub: d, Workflow: w})
+		if err != nil {
+			return err
+		}
+		if w.On != nil && w.On.WorkflowCall != nil {
+			for _, inputName := range sortedKeys(w.On.WorkflowCall.Inputs) {
+				if w.On.WorkflowCall.Inputs[inputName] == nil {
+					continue
+				}
+				err = visit(&Node{Kind: NodeWorkflowInput, DotGithub: d, Workflow: w, WorkflowInput: w.On.WorkflowCall.Inputs[inputName], Placement: "call", Name: inputName})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if w.On != nil && w.On.WorkflowDispatch != nil {
+			for _, inputName := range sortedKeys(w.On.WorkflowDispatch.Inputs) {
+				if w.On.WorkflowDispatch.Inputs[inputName] == nil {
+					continue
+				}
+				err = visit(&Node{Kind: NodeWorkflowInput, DotGithub: d, Workflow: w, WorkflowInput: w.On.WorkflowDispatch.Inputs[inputName], Placement: "dispatch", Name: inputName})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		for _, jobName := range sortedKeys(w.Jobs) {
+			j := w.Jobs[jobName]
+			if j == nil {
+				continue
+			}
+			err = visit(&Node{Kind: NodeWorkflowJob, DotGithub: d, Workflow: w, Job: j, JobName: jobName})
+			if err != nil {
+				return err
+			}
+			for i, s := range j.Steps {
+				if s == nil {
+					continue
+				}
+				err = visit(&Node{Kind: NodeWorkflowStep, DotGithub: d, Workflow: w, Job: j, JobName: jobName, Step: s, StepIndex: i})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+)
+
+func init() {
+	registerBuiltin("EW001", "Cannot parse YAML of workflow file", nil)
+	registerBuiltin("EW002", "Cannot read workflow file", nil)
+	registerBuiltin("NW101", "Workflow file name should follow the naming convention, kebab by default", validateWorkflowFileName, NodeWorkflow)
+	registerBuiltin("NW102", "Workflow file name should have .yml extension", validateWorkflowFileExtension, NodeWorkflow)
+	registerBuiltin("NW103", "Env variable name should follow the naming convention, SCREAMING_SNAKE by default", validateWorkflowEnv, NodeWorkflow)
+	registerBuiltin("NW104", "Workflow name is empty", validateWorkflowName, NodeWorkflow)
+	registerBuiltin("NW106", "When workflow has only one job, it should be named 'main'", validateWorkflowSingleJobName, NodeWorkflow)
+	registerBuiltin("NW107", "Called variable name should follow the naming convention, SCREAMING_SNAKE by default", validateWorkflowCalledVarNames, NodeWorkflow)
+	registerBuiltin("EW201", "Called variable is invalid", validateCalledVarsInvalid, NodeWorkflow)
+	registerBuiltin("EW202", "Called input does not exist", validateWorkflowCalledInputs, NodeWorkflow)
+	registerBuiltin("EW254", "Called variable does not exist in provided list of available vars", validateWorkflowCalledVarsExist("vars"), NodeWorkflow)
+	registerBuiltin("EW255", "Called secret does not exist in provided list of available secrets", validateWorkflowCalledVarsExist("secrets"), NodeWorkflow)
+}
+
+// validateWorkflowFileName checks file name without the extension and an optional underscore prefix.
+func validateWorkflowFileName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	name := strings.TrimPrefix(n.Workflow.FileName, "_")
+	name = regexp.MustCompile(`\.y[a]{0,1}ml$`).ReplaceAllString(name, "")
+	m, desc, err := matchNaming(n, "NW101", config.NamingKebab, name)
+	if err != nil {
+		return validationErrors, err
+	}
+	if !m {
+		validationErrors = append(validationErrors, n.Finding("Workflow file name should "+desc))
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowFileExtension(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	m, err := regexp.MatchString(`\.yml$`, n.Workflow.Path)
+	if err != nil {
+		return validationErrors, err
+	}
+	if !m {
+		validationErrors = append(validationErrors, n.Finding("Workflow file name should have .yml extension"))
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowEnv(n *Node) ([]*finding.Finding, error) {
+	return validateEnvNames(n, "NW103", n.Workflow.Env)
+}
+
+// validateEnvNames is used for 'env' of a workflow, a job and a step.
+func validateEnvNames(n *Node, code string, env map[string]string) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	for _, envName := range sortedKeys(env) {
+		m, desc, err := matchNaming(n, code, config.NamingScreamingSnake, envName)
+		if err != nil {
+			return validationErrors, err
+		}
+		if !m {
+			validationErrors = append(validationErrors, n.Finding(fmt.Sprintf("Env variable name '%s' should %s", envName, desc)))
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if n.Workflow.Name == "" {
+		validationErrors = append(validationErrors, n.Finding("Workflow name is empty"))
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowSingleJobName(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	if len(n.Workflow.Jobs) == 1 {
+		for jobName := range n.Workflow.Jobs {
+			if jobName != "main" {
+				validationErrors = append(validationErrors, n.Finding("When workflow has only one job, it should be named 'main'"))
+			}
+		}
+	}
+	return validationErrors, nil
+}
+
+func validateWorkflowCalledVarNames(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	varTypes := []string{"env", "vars", "secrets"}
+	for _, v := range varTypes {
+		re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*%s\\.([a-zA-Z0-9\\-_]+)[ ]*}}", v))
+		found := re.FindAllSubmatchIndex(n.Raw(), -1)
+		for _, f := range found {
+			called := string(n.Raw()[f[2]:f[3]])
+			m, desc, err := matchNaming(n, "NW107", config.NamingScreamingSnake, called)
+			if err != nil {
+				return validationErrors, err
+			}
+			if !m {
+				validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called variable name '%s' should %s", called, desc)))
+			}
+		}
+	}
+	return validationErrors, nil
+}
+
+// validateWorkflowCalledVarsExist checks called 'vars' or 'secrets' against the list of available names, if any.
+func validateWorkflowCalledVarsExist(varType string) func(n *Node) ([]*finding.Finding, error) {
+	return func(n *Node) ([]*finding.Finding, error) {
+		var validationErrors []*finding.Finding
+		if varType == "vars" && !n.DotGithub.IsVarsFileExist() {
+			return validationErrors, nil
+		}
+		if varType == "secrets" && !n.DotGithub.IsSecretsFileExist() {
+			return validationErrors, nil
+		}
+		re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*%s\\.([a-zA-Z0-9\\-_]+)[ ]*}}", varType))
+		found := re.FindAllSubmatchIndex(n.Raw(), -1)
+		for _, f := range found {
+			called := string(n.Raw()[f[2]:f[3]])
+			if varType == "vars" && !n.DotGithub.IsVarExist(called) {
+				validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called variable '%s' does not exist in provided list of available vars", called)))
+			}
+			if varType == "secrets" && !n.DotGithub.IsSecretExist(called) {
+				validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called secret '%s' does not exist in provided list of available secrets", called)))
+			}
+		}
+		return validationErrors, nil
+	}
+}
+
+func validateWorkflowCalledInputs(n *Node) ([]*finding.Finding, error) {
+	var validationErrors []*finding.Finding
+	w := n.Workflow
+	re := regexp.MustCompile(fmt.Sprintf("\\${{[ ]*inputs\\.([a-zA-Z0-9\\-_]+)[ ]*}}"))
+	found := re.FindAllSubmatchIndex(n.Raw(), -1)
+	for _, f := range found {
+		called := string(n.Raw()[f[2]:f[3]])
+		notInInputs := true
+		if w.On != nil {
+			if w.On.WorkflowCall != nil && w.On.WorkflowCall.Inputs != nil && w.On.WorkflowCall.Inputs[called] != nil {
+				notInInputs = false
+			}
+			if w.On.WorkflowDispatch != nil && w.On.WorkflowDispatch.Inputs != nil && w.On.WorkflowDispatch.Inputs[called] != nil {
+				notInInputs = false
+			}
+		}
+		if notInInputs {
+			validationErrors = append(validationErrors, n.FindingAtLine(n.LineAt(f[0]), fmt.Sprintf("Called input '%s' does not exist", called)))
+		}
+	}
+	return validationErrors, nil
+}
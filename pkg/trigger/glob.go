@@ -0,0 +1,71 @@
+package trigger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MatchGlob matches the string against a filter pattern with GitHub's semantics: '*' does not match '/', '**'
+// matches anything, '?' and '+' make the preceding character optional or repeated, '[]' is a character class
+// and '\' escapes a special character.
+func MatchGlob(pattern string, s string) bool {
+	re, err := regexp.Compile(globToRegexp(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?', '+':
+			b.WriteByte(c)
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+				continue
+			}
+			b.WriteString(pattern[i : i+end+2])
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				b.WriteString(regexp.QuoteMeta(pattern[i+1 : i+2]))
+				i++
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// matchPatterns returns true when the last pattern matching the string is not negated with '!'.
+func matchPatterns(patterns []string, s string) bool {
+	matched := false
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			if MatchGlob(p[1:], s) {
+				matched = false
+			}
+			continue
+		}
+		if MatchGlob(p, s) {
+			matched = true
+		}
+	}
+	return matched
+}
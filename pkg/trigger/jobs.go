@@ -0,0 +1,127 @@
+package trigger
+
+import (
+	"sort"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/expr"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
+)
+
+// EvalJobs tells which jobs of the triggered workflow run, based on their 'if' fields and the jobs they need.
+// Jobs are sorted by name.
+func EvalJobs(w *workflow.Workflow, e *Event) []*JobResult {
+	results := map[string]*JobResult{}
+	names := make([]string, 0, len(w.Jobs))
+	for n := range w.Jobs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var jobs []*JobResult
+	for _, n := range names {
+		jobs = append(jobs, evalJob(w, n, eventValues(e), results, map[string]bool{}))
+	}
+	return jobs
+}
+
+func evalJob(w *workflow.Workflow, name string, values expr.Values, results map[string]*JobResult, visiting map[string]bool) *JobResult {
+	if r, ok := results[name]; ok {
+		return r
+	}
+	j := w.Jobs[name]
+	r := &JobResult{Name: name, Runs: true, Known: true}
+	if j == nil {
+		return r
+	}
+	r.If = j.If
+	results[name] = r
+	visiting[name] = true
+
+	needsSkipped := ""
+	needs := 0
+	for _, n := range j.GetNeeds() {
+		if w.Jobs[n] == nil || visiting[n] {
+			continue
+		}
+		needs++
+		nr := evalJob(w, n, values, results, visiting)
+		if nr.Known && !nr.Runs && needsSkipped == "" {
+			needsSkipped = n
+		}
+	}
+	delete(visiting, name)
+
+	var cond *expr.Condition
+	if j.If != "" {
+		var err error
+		cond, err = expr.ParseCondition(j.If)
+		if err != nil {
+			r.Known = false
+			r.Reason = "'if' cannot be parsed: " + err.Error()
+			return r
+		}
+	}
+	if needsSkipped != "" && (cond == nil || !cond.HasStatusFunction()) {
+		r.Runs = false
+		r.Reason = "needed job '" + needsSkipped + "' is skipped"
+		return r
+	}
+	if cond == nil {
+		return r
+	}
+
+	jobValues := values
+	if needs == 0 {
+		// Job without needs starts when nothing has failed yet.
+		jobValues = expr.Values{"success()": true, "failure()": false, "cancelled()": false}
+		for k, v := range values {
+			jobValues[k] = v
+		}
+	}
+	r.Runs, r.Known = cond.Eval(jobValues, false)
+	switch {
+	case !r.Known:
+		r.Reason = "'if' cannot be evaluated statically"
+	case r.Runs:
+		r.Reason = "'if' is true"
+	default:
+		r.Reason = "'if' is false"
+	}
+	return r
+}
+
+// noTypeEvents do not have activity types, so their payload does not have 'action' field.
+var noTypeEvents = map[string]bool{
+	"push":              true,
+	"schedule":          true,
+	"workflow_dispatch": true,
+	"create":            true,
+	"delete":            true,
+}
+
+// eventValues returns values of 'github' context that are known for the event.
+func eventValues(e *Event) expr.Values {
+	values := expr.Values{"github.event_name": e.Name}
+	if e.Type != "" {
+		values["github.event.action"] = e.Type
+	} else if noTypeEvents[e.Name] {
+		values["github.event.action"] = nil
+	}
+	switch {
+	case e.Name == "push" && e.Tag != "":
+		values["github.ref"] = "refs/tags/" + e.Tag
+		values["github.ref_name"] = e.Tag
+		values["github.ref_type"] = "tag"
+	case e.Name == "push" && e.Branch != "":
+		values["github.ref"] = "refs/heads/" + e.Branch
+		values["github.ref_name"] = e.Branch
+		values["github.ref_type"] = "branch"
+	case (e.Name == "pull_request" || e.Name == "pull_request_target") && e.Branch != "":
+		values["github.base_ref"] = e.Branch
+		values["github.event.pull_request.base.ref"] = e.Branch
+	}
+	if e.Name != "pull_request" && e.Name != "pull_request_target" {
+		values["github.base_ref"] = ""
+		values["github.head_ref"] = ""
+	}
+	return values
+}
@@ -0,0 +1,204 @@
+// Package trigger tells which workflows would start for an event, by evaluating their 'on' filters, and which
+// of their jobs would run when it can be decided statically.
+package trigger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/workflow"
+)
+
+// Event is a simulated event.  Branch is the pushed branch, or the base branch of a pull request.  Type is the
+// activity type, eg. 'opened', and Workflow is the name of the workflow that triggers 'workflow_run'.  Filters
+// that need a field that is empty are not evaluated and are reported in Result.NotChecked.
+type Event struct {
+	Name     string
+	Branch   string
+	Tag      string
+	Type     string
+	Files    []string
+	Workflow string
+}
+
+// Result is the outcome for a single workflow.  Reason explains why it is not triggered.
+type Result struct {
+	Workflow   string
+	Triggered  bool
+	Reason     string
+	NotChecked []string
+	Jobs       []*JobResult
+}
+
+// JobResult tells whether a job runs.  When Known is false, it depends on values that are not known before the
+// workflow runs.
+type JobResult struct {
+	Name   string
+	If     string
+	Runs   bool
+	Known  bool
+	Reason string
+}
+
+var defaultTypes = map[string][]string{
+	"pull_request":        {"opened", "synchronize", "reopened"},
+	"pull_request_target": {"opened", "synchronize", "reopened"},
+}
+
+var branchEvents = map[string]bool{
+	"push":                true,
+	"pull_request":        true,
+	"pull_request_target": true,
+	"workflow_run":        true,
+}
+
+var pathEvents = map[string]bool{
+	"push":                true,
+	"pull_request":        true,
+	"pull_request_target": true,
+}
+
+// Simulate evaluates all the workflows, sorted by file name.
+func Simulate(d *dotgithub.DotGithub, e *Event) []*Result {
+	var results []*Result
+	names := make([]string, 0, len(d.Workflows))
+	for n := range d.Workflows {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		r := Match(d.Workflows[n], e)
+		r.Workflow = n
+		if r.Triggered {
+			r.Jobs = EvalJobs(d.Workflows[n], e)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Match evaluates 'on' filters of the workflow for the event.
+func Match(w *workflow.Workflow, e *Event) *Result {
+	r := &Result{}
+	if !w.On.IsEvent(e.Name) {
+		r.Reason = fmt.Sprintf("not triggered by '%s' event", e.Name)
+		return r
+	}
+	ev := w.On.Events[e.Name]
+
+	types := []string(ev.Types)
+	if len(types) == 0 {
+		types = defaultTypes[e.Name]
+	}
+	if len(types) > 0 {
+		if e.Type == "" {
+			r.NotChecked = append(r.NotChecked, "types")
+		} else if !containsFold(types, e.Type) {
+			r.Reason = fmt.Sprintf("activity type '%s' is not in %s", e.Type, strings.Join(types, ", "))
+			return r
+		}
+	}
+
+	isTag := e.Name == "push" && e.Tag != ""
+	if isTag {
+		if reason := matchRef("tag", e.Tag, ev.Tags, ev.TagsIgnore, ev.Branches, ev.BranchesIgnore); reason != "" {
+			r.Reason = reason
+			return r
+		}
+	} else if branchEvents[e.Name] {
+		tags := []string(ev.Tags)
+		tagsIgnore := []string(ev.TagsIgnore)
+		if e.Name != "push" {
+			tags, tagsIgnore = nil, nil
+		}
+		if e.Branch == "" && (len(ev.Branches) > 0 || len(ev.BranchesIgnore) > 0) {
+			r.NotChecked = append(r.NotChecked, "branches")
+		} else if reason := matchRef("branch", e.Branch, ev.Branches, ev.BranchesIgnore, tags, tagsIgnore); reason != "" {
+			r.Reason = reason
+			return r
+		}
+	}
+
+	// Path filters are not evaluated for pushes of tags.
+	if pathEvents[e.Name] && !isTag && (len(ev.Paths) > 0 || len(ev.PathsIgnore) > 0) {
+		if len(e.Files) == 0 {
+			r.NotChecked = append(r.NotChecked, "paths")
+		} else if reason := matchPaths(e.Files, ev.Paths, ev.PathsIgnore); reason != "" {
+			r.Reason = reason
+			return r
+		}
+	}
+
+	if e.Name == "workflow_run" && len(ev.Workflows) > 0 {
+		if e.Workflow == "" {
+			r.NotChecked = append(r.NotChecked, "workflows")
+		} else if !containsFold(ev.Workflows, e.Workflow) {
+			r.Reason = fmt.Sprintf("workflow '%s' is not in %s", e.Workflow, strings.Join(ev.Workflows, ", "))
+			return r
+		}
+	}
+
+	r.Triggered = true
+	return r
+}
+
+// matchRef checks a branch or a tag against its filters.  When only the filters of the other kind are set,
+// eg. only 'branches' for a tag, the workflow is not triggered.
+func matchRef(kind string, ref string, filters []string, ignore []string, otherFilters []string, otherIgnore []string) string {
+	if len(filters) == 0 && len(ignore) == 0 {
+		if len(otherFilters) > 0 || len(otherIgnore) > 0 {
+			return fmt.Sprintf("only %s filters are set", otherKind(kind))
+		}
+		return ""
+	}
+	if len(filters) > 0 && !matchPatterns(filters, ref) {
+		return fmt.Sprintf("%s '%s' does not match %s filters", kind, ref, pluralKind(kind))
+	}
+	if len(ignore) > 0 && matchPatterns(ignore, ref) {
+		return fmt.Sprintf("%s '%s' matches %s-ignore filters", kind, ref, pluralKind(kind))
+	}
+	return ""
+}
+
+// matchPaths returns a reason when none of the files matches 'paths', or all of them match 'paths-ignore'.
+func matchPaths(files []string, paths []string, ignore []string) string {
+	if len(paths) > 0 {
+		for _, f := range files {
+			if matchPatterns(paths, f) {
+				return ""
+			}
+		}
+		return "none of the changed files matches paths filters"
+	}
+	for _, f := range files {
+		if !matchPatterns(ignore, f) {
+			return ""
+		}
+	}
+	return "all the changed files match paths-ignore filters"
+}
+
+func otherKind(kind string) string {
+	if kind == "tag" {
+		return "branch"
+	}
+	return "tag"
+}
+
+func pluralKind(kind string) string {
+	if kind == "branch" {
+		return "branches"
+	}
+	return "tags"
+}
+
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+	return false
+}
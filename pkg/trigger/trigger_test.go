@@ -0,0 +1,87 @@
+package trigger
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"main", "main", true},
+		{"main", "main2", false},
+		{"feature/*", "feature/a", true},
+		{"feature/*", "feature/a/b", false},
+		{"feature/**", "feature/a/b", true},
+		{"**.js", "src/app/index.js", true},
+		{"*.js", "src/index.js", false},
+		{"v[0-9]+.*", "v12.0", true},
+		{"v[0-9]+.*", "v.0", false},
+		{"v1.?", "v1.", true},
+		{"v1.?", "v1.2", false},
+		{"docs/\\*", "docs/*", true},
+		{"docs/\\*", "docs/a", false},
+		{"a.b", "axb", false},
+		{"[ab", "[ab", true},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	d := &dotgithub.DotGithub{
+		Path: filepath.Join("testdata", ".github"),
+	}
+	err := d.InitFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		workflow string
+		event    Event
+		want     string
+	}{
+		{"branches.yml", Event{Name: "push", Branch: "main"}, "triggered, not checked: [paths]"},
+		{"branches.yml", Event{Name: "push", Branch: "release/1.0", Files: []string{"go.mod"}}, "triggered"},
+		{"branches.yml", Event{Name: "push", Branch: "release/1.0-rc"}, "branch 'release/1.0-rc' does not match branches filters"},
+		{"branches.yml", Event{Name: "push", Branch: "dev"}, "branch 'dev' does not match branches filters"},
+		{"branches.yml", Event{Name: "push", Tag: "v1.0"}, "only branch filters are set"},
+		{"branches.yml", Event{Name: "push", Branch: "main", Files: []string{"docs/a.md"}}, "all the changed files match paths-ignore filters"},
+		{"branches.yml", Event{Name: "push", Branch: "main", Files: []string{"docs/a.md", "main.go"}}, "triggered"},
+		{"branches.yml", Event{Name: "push"}, "triggered, not checked: [branches paths]"},
+		{"branches.yml", Event{Name: "pull_request", Branch: "main", Type: "labeled"}, "triggered"},
+		{"branches.yml", Event{Name: "pull_request", Branch: "main", Type: "closed"}, "activity type 'closed' is not in opened, labeled"},
+		{"branches.yml", Event{Name: "pull_request"}, "triggered, not checked: [types branches]"},
+		{"branches.yml", Event{Name: "release"}, "not triggered by 'release' event"},
+		{"tags.yml", Event{Name: "push", Tag: "v1.2"}, "triggered"},
+		{"tags.yml", Event{Name: "push", Tag: "latest"}, "tag 'latest' does not match tags filters"},
+		{"tags.yml", Event{Name: "push", Branch: "main"}, "only tag filters are set"},
+		{"tags.yml", Event{Name: "workflow_run", Workflow: "Branches"}, "triggered"},
+		{"tags.yml", Event{Name: "workflow_run", Workflow: "other"}, "workflow 'other' is not in branches"},
+		{"paths.yml", Event{Name: "pull_request", Type: "opened", Files: []string{"pkg/a.go"}}, "triggered"},
+		{"paths.yml", Event{Name: "pull_request", Type: "opened", Files: []string{"pkg/a_test.go", "README.md"}}, "none of the changed files matches paths filters"},
+		{"paths.yml", Event{Name: "pull_request", Type: "opened"}, "triggered, not checked: [paths]"},
+	}
+	for _, tt := range tests {
+		r := Match(d.Workflows[tt.workflow], &tt.event)
+		got := r.Reason
+		if r.Triggered {
+			got = "triggered"
+			if len(r.NotChecked) > 0 {
+				got += fmt.Sprintf(", not checked: %v", r.NotChecked)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("Match(%s, %+v) = %q, want %q", tt.workflow, tt.event, got, tt.want)
+		}
+	}
+}
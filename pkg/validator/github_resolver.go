@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// GitHubResolver downloads action.yml (or action.yaml) of an external action from raw.githubusercontent.com.
+type GitHubResolver struct {
+	Client *http.Client
+}
+
+func (g *GitHubResolver) Resolve(ctx context.Context, path string) ([]byte, error) {
+	repoVersion := strings.Split(path, "@")
+	ownerRepoDir := strings.SplitN(repoVersion[0], "/", 3)
+	if len(repoVersion) != 2 || len(ownerRepoDir) < 2 {
+		return nil, fmt.Errorf("Invalid path to external action %s", path)
+	}
+	directory := ""
+	if len(ownerRepoDir) > 2 {
+		directory = "/" + ownerRepoDir[2]
+	}
+	actionURLPrefix := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", ownerRepoDir[0], ownerRepoDir[1], repoVersion[1])
+
+	for _, fileName := range []string{"action.yml", "action.yaml"} {
+		b, err := g.get(ctx, actionURLPrefix+directory+"/"+fileName)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (g *GitHubResolver) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, strings.NewReader(""))
+	if err != nil {
+		return nil, err
+	}
+	c := g.Client
+	if c == nil {
+		c = &http.Client{}
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, nil
+	}
+	return ioutil.ReadAll(resp.Body)
+}
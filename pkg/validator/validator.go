@@ -0,0 +1,145 @@
+// Package validator runs the validation of a .github directory and can be used to embed the validator in other
+// tools.  It does not write anything to the standard output.
+package validator
+
+import (
+	"context"
+	"io"
+
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/config"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/dotgithub"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/finding"
+	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/rule"
+)
+
+// Resolver returns contents of the action.yml file of an external action, eg. 'actions/checkout@v4'.  When the
+// action does not exist, it should return nil without an error.
+type Resolver interface {
+	Resolve(ctx context.Context, path string) ([]byte, error)
+}
+
+// Options configure a single run of the validator.
+//
+// Vars and Secrets are lists of available variable and secret names.  When nil, called variables and secrets
+// are not checked against them.  Rules is a list of enabled codes, eg. 'EW804' - when empty, all of them are
+// enabled.  CustomRules are added to the built-in ones and their codes must not clash with them.  Config changes
+// the behaviour of rules, eg. naming conventions.  Resolver defaults to GitHubResolver and progress messages are
+// written to Output, if it is set.
+type Options struct {
+	Path        string
+	Vars        []string
+	Secrets     []string
+	Resolver    Resolver
+	Rules       []string
+	CustomRules []rule.Rule
+	Config      *config.Config
+	Output      io.Writer
+}
+
+// Report contains findings of a run, sorted by file, position and code.
+type Report struct {
+	Findings []*finding.Finding
+}
+
+// HasErrors returns true when any of the findings is an error.
+func (r *Report) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == finding.SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Run reads and validates the .github directory from opts.Path.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Config != nil {
+		err := opts.Config.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	registry := rule.Builtin()
+	for _, r := range opts.CustomRules {
+		err := registry.Register(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resolver := opts.Resolver
+	if resolver == nil {
+		resolver = &GitHubResolver{}
+	}
+	d := &dotgithub.DotGithub{
+		Path:    opts.Path,
+		Vars:    namesToMap(opts.Vars),
+		Secrets: namesToMap(opts.Secrets),
+		Output:  opts.Output,
+		Resolver: &contextResolver{
+			ctx:      ctx,
+			resolver: resolver,
+		},
+	}
+
+	err := d.InitFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	findings, err := registry.Run(d, opts.Config, opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(filterRules(d.InitErrors(), opts.Rules), findings...)
+	err = finding.Sort(findings, finding.SortByFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		Findings: findings,
+	}, nil
+}
+
+func namesToMap(names []string) map[string]bool {
+	if names == nil {
+		return nil
+	}
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+	return m
+}
+
+func filterRules(findings []*finding.Finding, rules []string) []*finding.Finding {
+	if len(rules) == 0 {
+		return findings
+	}
+	enabled := namesToMap(rules)
+	filtered := []*finding.Finding{}
+	for _, f := range findings {
+		if enabled[f.Code] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// contextResolver binds context of the run to the Resolver so that it can be used by dotgithub.DotGithub.
+type contextResolver struct {
+	ctx      context.Context
+	resolver Resolver
+}
+
+func (c *contextResolver) Resolve(path string) ([]byte, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.resolver.Resolve(c.ctx, path)
+}
@@ -0,0 +1,22 @@
+package workflow
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// StringList is a field that can be either a single string or a list of strings, eg. 'types' of an event.
+type StringList []string
+
+func (sl *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*sl = StringList{value.Value}
+		return nil
+	}
+	var l []string
+	err := value.Decode(&l)
+	if err != nil {
+		return err
+	}
+	*sl = l
+	return nil
+}
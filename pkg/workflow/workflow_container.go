@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowContainer is the 'container' of a job or one of its 'services'.  Container can be set to just the
+// image name.
+type WorkflowContainer struct {
+	Line        int                  `yaml:"-"`
+	Image       string               `yaml:"image"`
+	Credentials *WorkflowCredentials `yaml:"credentials"`
+	Env         map[string]string    `yaml:"env"`
+	Ports       []string             `yaml:"ports"`
+	Volumes     []string             `yaml:"volumes"`
+	Options     string               `yaml:"options"`
+}
+
+func (wc *WorkflowContainer) UnmarshalYAML(value *yaml.Node) error {
+	wc.Line = value.Line
+	if value.Kind == yaml.ScalarNode {
+		wc.Image = value.Value
+		return nil
+	}
+	type plain WorkflowContainer
+	err := value.Decode((*plain)(wc))
+	if err != nil {
+		return err
+	}
+	wc.Line = value.Line
+	return nil
+}
+
+type WorkflowCredentials struct {
+	Line     int    `yaml:"-"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func (wc *WorkflowCredentials) UnmarshalYAML(value *yaml.Node) error {
+	type plain WorkflowCredentials
+	err := value.Decode((*plain)(wc))
+	if err != nil {
+		return err
+	}
+	wc.Line = value.Line
+	return nil
+}
@@ -0,0 +1,26 @@
+package workflow
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowDefaults is the 'defaults' field of a workflow or a job.
+type WorkflowDefaults struct {
+	Run *WorkflowDefaultsRun `yaml:"run"`
+}
+
+type WorkflowDefaultsRun struct {
+	Line             int    `yaml:"-"`
+	Shell            string `yaml:"shell"`
+	WorkingDirectory string `yaml:"working-directory"`
+}
+
+func (wdr *WorkflowDefaultsRun) UnmarshalYAML(value *yaml.Node) error {
+	type plain WorkflowDefaultsRun
+	err := value.Decode((*plain)(wdr))
+	if err != nil {
+		return err
+	}
+	wdr.Line = value.Line
+	return nil
+}
@@ -0,0 +1,15 @@
+package workflow
+
+// WorkflowEvent is an event from the 'on' field with its filters.  Filters that do not apply to the event are
+// empty.
+type WorkflowEvent struct {
+	Line           int        `yaml:"-"`
+	Types          StringList `yaml:"types"`
+	Branches       StringList `yaml:"branches"`
+	BranchesIgnore StringList `yaml:"branches-ignore"`
+	Tags           StringList `yaml:"tags"`
+	TagsIgnore     StringList `yaml:"tags-ignore"`
+	Paths          StringList `yaml:"paths"`
+	PathsIgnore    StringList `yaml:"paths-ignore"`
+	Workflows      StringList `yaml:"workflows"`
+}
@@ -1,32 +1,22 @@
 package workflow
 
 import (
-	"fmt"
-	"regexp"
+	"gopkg.in/yaml.v3"
 )
 
 type WorkflowInput struct {
+	Line        int    `yaml:"-"`
 	Description string `yaml:"description"`
 	Default     string `yaml:"default"`
 	Required    bool   `yaml:"required"`
 }
 
-func (wi *WorkflowInput) Validate(workflow string, placement string, name string) ([]string, error) {
-	var validationErrors []string
-	m, err := regexp.MatchString(`^[a-z0-9][a-z0-9\-]+$`, name)
+func (wi *WorkflowInput) UnmarshalYAML(value *yaml.Node) error {
+	type plain WorkflowInput
+	err := value.Decode((*plain)(wi))
 	if err != nil {
-		return validationErrors, err
+		return err
 	}
-	if !m {
-		validationErrors = append(validationErrors, wi.formatError(workflow, placement, name, "NW301", "Workflow input name should contain lowercase alphanumeric characters and hyphens only"))
-	}
-
-	if wi.Description == "" {
-		validationErrors = append(validationErrors, wi.formatError(workflow, placement, name, "NW302", "Workflow input must have a description"))
-	}
-	return validationErrors, nil
-}
-
-func (wi *WorkflowInput) formatError(workflow string, placement string, input string, code string, desc string) string {
-	return fmt.Sprintf("%s: %-80s %s", code, "workflow "+workflow+" "+placement+" input "+input, desc)
+	wi.Line = value.Line
+	return nil
 }
@@ -2,116 +2,62 @@ package workflow
 
 import (
 	"fmt"
+	"gopkg.in/yaml.v3"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/Cardinal-Cryptography/github-actions-validator/pkg/action"
 )
 
 type WorkflowJob struct {
-	Name   string               `yaml:"name"`
-	Uses   string               `yaml:"uses"`
-	RunsOn interface{}          `yaml:"runs-on"`
-	Steps  []*action.ActionStep `yaml:"steps"`
-	Env    map[string]string    `yaml:"env"`
-	Needs  interface{}          `yaml:"needs,omitempty"`
+	Line        int                           `yaml:"-"`
+	Name        string                        `yaml:"name"`
+	If          string                        `yaml:"if"`
+	IfLine      int                           `yaml:"-"`
+	Uses        string                        `yaml:"uses"`
+	RunsOn      *WorkflowRunsOn               `yaml:"runs-on"`
+	Steps       []*action.ActionStep          `yaml:"steps"`
+	Env         map[string]string             `yaml:"env"`
+	Needs       interface{}                   `yaml:"needs,omitempty"`
+	Permissions *WorkflowPermissions          `yaml:"permissions"`
+	Defaults    *WorkflowDefaults             `yaml:"defaults"`
+	Container   *WorkflowContainer            `yaml:"container"`
+	Services    map[string]*WorkflowContainer `yaml:"services"`
 }
 
-func (wj *WorkflowJob) SetParentType(t string) {
-	for _, s := range wj.Steps {
-		s.ParentType = t
-	}
-}
-
-func (wj *WorkflowJob) Validate(workflow string, job string, d IDotGithub) ([]string, error) {
-	var validationErrors []string
-	verr, err := wj.validateName(workflow, job)
+func (wj *WorkflowJob) UnmarshalYAML(value *yaml.Node) error {
+	type plain WorkflowJob
+	err := value.Decode((*plain)(wj))
 	if err != nil {
-		return validationErrors, err
+		return err
 	}
-	validationErrors = wj.appendErr(validationErrors, verr)
-
-	if wj.RunsOn != nil {
-		runsOnStr, ok := wj.RunsOn.(string)
-		if ok {
-			if wj.Uses == "" && runsOnStr == "" {
-				validationErrors = append(validationErrors, wj.formatError(workflow, job, "EW601", "Workflow job name should have either 'uses' or 'runs-on'"))
-			}
-			if strings.Contains(runsOnStr, "latest") {
-				validationErrors = append(validationErrors, wj.formatError(workflow, job, "EW602", "Workflow job should not have 'latest' in 'runs-on'"))
-			}
+	wj.Line = value.Line
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value == "if" {
+			wj.IfLine = value.Content[i].Line
 		}
-
-		runsOnList, ok := wj.RunsOn.([]string)
-		if ok {
-			for _, runsOn := range runsOnList {
-				if strings.Contains(runsOn, "latest") {
-					validationErrors = append(validationErrors, wj.formatError(workflow, job, "EW602", "Workflow job should not have 'latest' in 'runs-on'"))
-				}
-			}
-		}
-	}
-
-	verrs, err := wj.validateEnv(workflow, job)
-	if err != nil {
-		return validationErrors, err
-	}
-	validationErrors = wj.appendErrs(validationErrors, verrs)
-
-	verrs, err = wj.validateSteps(workflow, job, d)
-	if err != nil {
-		return validationErrors, err
-	}
-	validationErrors = wj.appendErrs(validationErrors, verrs)
-	return validationErrors, nil
-}
-
-func (wj *WorkflowJob) appendErr(list []string, err string) []string {
-	if err != "" {
-		list = append(list, err)
 	}
-	return list
+	return nil
 }
 
-func (wj *WorkflowJob) appendErrs(list []string, errs []string) []string {
-	if len(errs) > 0 {
-		for _, err := range errs {
-			list = wj.appendErr(list, err)
+// GetNeeds returns names of the jobs from 'needs' field, which can be a string or a list.
+func (wj *WorkflowJob) GetNeeds() []string {
+	needs := []string{}
+	switch v := wj.Needs.(type) {
+	case string:
+		needs = append(needs, v)
+	case []interface{}:
+		for _, n := range v {
+			needs = append(needs, fmt.Sprintf("%v", n))
 		}
 	}
-	return list
-}
-
-func (wj *WorkflowJob) validateName(workflow string, job string) (string, error) {
-	m, err := regexp.MatchString(`^[a-z0-9][a-z0-9\-]+$`, job)
-	if err != nil {
-		return "", err
-	}
-	if !m {
-		return wj.formatError(workflow, job, "NW501", "Workflow job name should contain lowercase alphanumeric characters and hyphens only"), nil
-	}
-	return "", nil
+	return needs
 }
 
-func (wj *WorkflowJob) validateEnv(workflow string, job string) ([]string, error) {
-	var validationErrors []string
-	if wj.Env != nil {
-		for envName := range wj.Env {
-			m, err := regexp.MatchString(`^[A-Z][A-Z0-9_]+$`, envName)
-			if err != nil {
-				return validationErrors, err
-			}
-			if !m {
-				validationErrors = append(validationErrors, wj.formatError(workflow, job, "NW502", fmt.Sprintf(workflow, job, "Env variable name '%s' should contain uppercase alphanumeric characters and underscore only", envName)))
-			}
-		}
+func (wj *WorkflowJob) SetParentType(t string) {
+	for _, s := range wj.Steps {
+		s.ParentType = t
 	}
-	return validationErrors, nil
-}
-
-func (wj *WorkflowJob) formatError(workflow string, job string, code string, desc string) string {
-	return fmt.Sprintf("%s: %-60s %s", code, "workflow "+workflow+" job "+job, desc)
 }
 
 func (wj *WorkflowJob) IsStepExist(id string) bool {
@@ -123,20 +69,8 @@ func (wj *WorkflowJob) IsStepExist(id string) bool {
 	return false
 }
 
-func (wj *WorkflowJob) validateSteps(workflow string, job string, d IDotGithub) ([]string, error) {
-	var validationErrors []string
-	if wj.Steps != nil {
-		for i, s := range wj.Steps {
-			verrs, err := s.Validate(workflow, job, strconv.Itoa(i), d)
-			if err != nil {
-				return validationErrors, err
-			}
-			validationErrors = wj.appendErrs(validationErrors, verrs)
-		}
-	}
-	return validationErrors, nil
-}
-
+// IsStepOutputExist returns 0 when the step sets the output, 1 when it sets it with deprecated '::set-output'
+// command, -1 when there is no such step and -2 when the step does not have the output.
 func (wj *WorkflowJob) IsStepOutputExist(step string, output string, d IDotGithub) int {
 	for _, s := range wj.Steps {
 		if s.Id != step {
@@ -144,14 +78,7 @@ func (wj *WorkflowJob) IsStepOutputExist(step string, output string, d IDotGithu
 		}
 
 		if s.Uses == "" && s.Run != "" {
-			re := regexp.MustCompile(`echo[ ]+"([a-zA-Z0-9\-_]+)=.*"[ ]+.*>>[ ]+\$GITHUB_OUTPUT`)
-			found := re.FindAllSubmatch([]byte(s.Run), -1)
-			for _, f := range found {
-				if output == string(f[1]) {
-					return 0
-				}
-			}
-			return -2
+			return s.GetRunOutput(output)
 		}
 
 		re := regexp.MustCompile(`^\.\/\.github\/actions\/[a-z0-9\-]+$`)
@@ -1,41 +1,93 @@
 package workflow
 
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+// WorkflowOn is the 'on' field of a workflow, which can be a single event name, a list of event names or a map
+// of events with their configuration.  Every event is in Events, and 'workflow_call', 'workflow_dispatch' and
+// 'schedule' are additionally parsed into their own fields.
 type WorkflowOn struct {
-	WorkflowCall     *WorkflowCall     `yaml:"workflow_call"`
-	WorkflowDispatch *WorkflowDispatch `yaml:"workflow_dispatch"`
+	Line             int                       `yaml:"-"`
+	Events           map[string]*WorkflowEvent `yaml:"-"`
+	WorkflowCall     *WorkflowCall             `yaml:"-"`
+	WorkflowDispatch *WorkflowDispatch         `yaml:"-"`
+	Schedule         []*WorkflowSchedule       `yaml:"-"`
 }
 
-func (wo *WorkflowOn) Validate(workflow string) ([]string, error) {
-	var validationErrors []string
-	if wo.WorkflowCall != nil {
-		verrs, err := wo.WorkflowCall.Validate(workflow)
-		if err != nil {
-			return validationErrors, err
+func (wo *WorkflowOn) UnmarshalYAML(value *yaml.Node) error {
+	wo.Line = value.Line
+	wo.Events = map[string]*WorkflowEvent{}
+	switch value.Kind {
+	case yaml.ScalarNode:
+		wo.Events[value.Value] = &WorkflowEvent{Line: value.Line}
+	case yaml.SequenceNode:
+		for _, e := range value.Content {
+			if e.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: event name should be a string", e.Line)
+			}
+			wo.Events[e.Value] = &WorkflowEvent{Line: e.Line}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			k, v := value.Content[i], value.Content[i+1]
+			err := wo.unmarshalEvent(k.Value, k.Line, v)
+			if err != nil {
+				return err
+			}
 		}
-		validationErrors = wo.appendErrs(validationErrors, verrs)
+	default:
+		return fmt.Errorf("line %d: 'on' should be an event name, a list or a map of events", value.Line)
 	}
-	if wo.WorkflowDispatch != nil {
-		verrs, err := wo.WorkflowDispatch.Validate(workflow)
+	return nil
+}
+
+func (wo *WorkflowOn) unmarshalEvent(name string, line int, value *yaml.Node) error {
+	e := &WorkflowEvent{}
+	isNull := value.Kind == yaml.ScalarNode && value.Tag == "!!null"
+	if !isNull {
+		var err error
+		switch name {
+		case "workflow_call":
+			wo.WorkflowCall = &WorkflowCall{}
+			err = value.Decode(wo.WorkflowCall)
+		case "workflow_dispatch":
+			wo.WorkflowDispatch = &WorkflowDispatch{}
+			err = value.Decode(wo.WorkflowDispatch)
+		case "schedule":
+			err = value.Decode(&wo.Schedule)
+		default:
+			err = value.Decode(e)
+		}
 		if err != nil {
-			return validationErrors, err
+			return err
 		}
-		validationErrors = wo.appendErrs(validationErrors, verrs)
+	} else if name == "workflow_call" {
+		wo.WorkflowCall = &WorkflowCall{}
+	} else if name == "workflow_dispatch" {
+		wo.WorkflowDispatch = &WorkflowDispatch{}
 	}
-	return validationErrors, nil
+	e.Line = line
+	wo.Events[name] = e
+	return nil
 }
 
-func (wo *WorkflowOn) appendErr(list []string, err string) []string {
-	if err != "" {
-		list = append(list, err)
-	}
-	return list
+// IsEvent returns true when workflow is triggered by the event.
+func (wo *WorkflowOn) IsEvent(name string) bool {
+	return wo != nil && wo.Events[name] != nil
 }
 
-func (wo *WorkflowOn) appendErrs(list []string, errs []string) []string {
-	if len(errs) > 0 {
-		for _, err := range errs {
-			list = wo.appendErr(list, err)
-		}
+// EventNames returns sorted names of all the events that trigger the workflow.
+func (wo *WorkflowOn) EventNames() []string {
+	var names []string
+	if wo == nil {
+		return names
+	}
+	for n := range wo.Events {
+		names = append(names, n)
 	}
-	return list
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,21 @@
+package workflow
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+type WorkflowOutput struct {
+	Line        int    `yaml:"-"`
+	Description string `yaml:"description"`
+	Value       string `yaml:"value"`
+}
+
+func (wo *WorkflowOutput) UnmarshalYAML(value *yaml.Node) error {
+	type plain WorkflowOutput
+	err := value.Decode((*plain)(wo))
+	if err != nil {
+		return err
+	}
+	wo.Line = value.Line
+	return nil
+}
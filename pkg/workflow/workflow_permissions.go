@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PermissionsReadAll  = "read-all"
+	PermissionsWriteAll = "write-all"
+	PermissionRead      = "read"
+	PermissionWrite     = "write"
+	PermissionNone      = "none"
+)
+
+// WorkflowPermissions is the 'permissions' field of a workflow or a job.  It is either one of 'read-all' and
+// 'write-all' that is kept in All, or a map of scopes to their values.  Scopes that are not set have no access.
+type WorkflowPermissions struct {
+	Line       int
+	All        string
+	Scopes     map[string]string
+	ScopeLines map[string]int
+}
+
+func (wp *WorkflowPermissions) UnmarshalYAML(value *yaml.Node) error {
+	wp.Line = value.Line
+	wp.Scopes = map[string]string{}
+	wp.ScopeLines = map[string]int{}
+	if value.Kind == yaml.ScalarNode {
+		wp.All = value.Value
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: 'permissions' should be 'read-all', 'write-all' or a map of scopes", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		k, v := value.Content[i], value.Content[i+1]
+		if v.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: permission of '%s' should be a string", v.Line, k.Value)
+		}
+		wp.Scopes[k.Value] = v.Value
+		wp.ScopeLines[k.Value] = k.Line
+	}
+	return nil
+}
+
+// Get returns 'read', 'write' or 'none' access to the scope.
+func (wp *WorkflowPermissions) Get(scope string) string {
+	switch wp.All {
+	case PermissionsReadAll:
+		return PermissionRead
+	case PermissionsWriteAll:
+		return PermissionWrite
+	}
+	if wp.Scopes[scope] == "" {
+		return PermissionNone
+	}
+	return wp.Scopes[scope]
+}
+
+// HasWrite returns true when any of the scopes can write.
+func (wp *WorkflowPermissions) HasWrite() bool {
+	if wp.All == PermissionsWriteAll {
+		return true
+	}
+	for _, v := range wp.Scopes {
+		if v == PermissionWrite {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// WorkflowRunsOn is the 'runs-on' field of a job, which can be a single label, a list of labels, a map with
+// 'group' and 'labels', or an expression.  When the whole field is an expression, it is in Expression and
+// Labels is empty.  Labels can contain expressions too.
+type WorkflowRunsOn struct {
+	Line       int
+	Labels     []string
+	Group      string
+	Expression string
+}
+
+func (ro *WorkflowRunsOn) UnmarshalYAML(value *yaml.Node) error {
+	ro.Line = value.Line
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if strings.HasPrefix(strings.TrimSpace(value.Value), "${{") {
+			ro.Expression = value.Value
+		} else if value.Value != "" {
+			ro.Labels = []string{value.Value}
+		}
+	case yaml.SequenceNode:
+		return value.Decode(&ro.Labels)
+	case yaml.MappingNode:
+		var m struct {
+			Group  string     `yaml:"group"`
+			Labels StringList `yaml:"labels"`
+		}
+		err := value.Decode(&m)
+		if err != nil {
+			return err
+		}
+		ro.Group = m.Group
+		ro.Labels = m.Labels
+	default:
+		return fmt.Errorf("line %d: 'runs-on' should be a label, a list of labels or a map with group and labels", value.Line)
+	}
+	return nil
+}
+
+// IsEmpty returns true when there is no label, group nor expression.
+func (ro *WorkflowRunsOn) IsEmpty() bool {
+	return ro == nil || (len(ro.Labels) == 0 && ro.Group == "" && ro.Expression == "")
+}
+
+// GetOS returns 'linux', 'windows' or 'macos' when any of the labels tells the operating system of the runner,
+// or an empty string when it is not known.
+func (ro *WorkflowRunsOn) GetOS() string {
+	if ro == nil {
+		return ""
+	}
+	for _, l := range ro.Labels {
+		l = strings.ToLower(l)
+		switch {
+		case strings.HasPrefix(l, "ubuntu") || l == "linux":
+			return "linux"
+		case strings.HasPrefix(l, "windows"):
+			return "windows"
+		case strings.HasPrefix(l, "macos"):
+			return "macos"
+		}
+	}
+	return ""
+}
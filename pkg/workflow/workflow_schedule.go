@@ -0,0 +1,5 @@
+package workflow
+
+type WorkflowSchedule struct {
+	Cron string `yaml:"cron"`
+}